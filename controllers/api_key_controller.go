@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"errors"
+	"go-webapi-example/middleware"
+	"go-webapi-example/models"
+	"go-webapi-example/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type APIKeyController struct {
+	apiKeyService *services.APIKeyService
+}
+
+func NewAPIKeyController(db *gorm.DB) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService: services.NewAPIKeyService(db),
+	}
+}
+
+// GetAPIKeys godoc
+// @Summary 获取个人 API 密钥列表
+// @Description 返回当前用户的全部 API 密钥（包括已撤销的），不包含密钥明文
+// @Tags api-keys
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.APIKey "获取成功"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /users/profile/api-keys [get]
+func (c *APIKeyController) GetAPIKeys(ctx *gin.Context) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	keys, err := c.apiKeyService.GetAPIKeys(userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// CreateAPIKey godoc
+// @Summary 创建个人 API 密钥
+// @Description 创建一个以当前用户身份访问 API 的密钥，密钥明文只在本次响应中返回。不能使用 API 密钥创建新的密钥
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.CreateAPIKeyRequest true "密钥信息"
+// @Success 201 {object} models.CreateAPIKeyResponse "创建成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "不能使用 API 密钥管理密钥"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /users/profile/api-keys [post]
+func (c *APIKeyController) CreateAPIKey(ctx *gin.Context) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	if !c.requireSession(ctx) {
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, err := c.apiKeyService.CreateAPIKey(userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrAPIKeyExpiryPast) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, key)
+}
+
+// RevokeAPIKey godoc
+// @Summary 撤销个人 API 密钥
+// @Description 撤销当前用户的指定 API 密钥，撤销后立即失效
+// @Tags api-keys
+// @Security ApiKeyAuth
+// @Param keyId path int true "密钥ID"
+// @Success 204 "撤销成功，无返回内容"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "不能使用 API 密钥管理密钥"
+// @Failure 404 {object} map[string]string "密钥不存在或已撤销"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /users/profile/api-keys/{keyId} [delete]
+func (c *APIKeyController) RevokeAPIKey(ctx *gin.Context) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	if !c.requireSession(ctx) {
+		return
+	}
+
+	keyID, err := strconv.ParseUint(ctx.Param("keyId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	if err := c.apiKeyService.RevokeAPIKey(userID.(uint), uint(keyID)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// requireSession 密钥的创建和撤销必须使用登录令牌，防止泄露的密钥自我续期
+func (c *APIKeyController) requireSession(ctx *gin.Context) bool {
+	if ctx.GetString("authMethod") == middleware.AuthMethodAPIKey {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "API keys cannot be managed with an API key, login with your password instead"})
+		return false
+	}
+	return true
+}
@@ -1,27 +1,41 @@
 package controllers
 
 import (
+	"errors"
+	"go-webapi-example/config"
 	"go-webapi-example/models"
 	"go-webapi-example/services"
+	"go-webapi-example/utils"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type AuthController struct {
-	userService *services.UserService
+	userService         *services.UserService
+	tokenService        *services.TokenService
+	revocationService   *services.RevocationService
+	organizationService *services.OrganizationService
+	cartService         *services.CartService
 }
 
-func NewAuthController(db *gorm.DB) *AuthController {
+func NewAuthController(db *gorm.DB, cfg *config.Config) *AuthController {
 	return &AuthController{
-		userService: services.NewUserService(db),
+		userService:         services.NewUserService(db, cfg),
+		tokenService:        services.NewTokenService(db, cfg),
+		revocationService:   services.NewRevocationService(db),
+		organizationService: services.NewOrganizationService(db),
+		cartService:         services.NewCartService(db, cfg),
 	}
 }
 
 // Login godoc
 // @Summary 用户登录
-// @Description 用户使用邮箱和密码登录系统，成功后返回JWT访问令牌
+// @Description 用户使用邮箱和密码登录系统，成功后返回短期JWT访问令牌和长期刷新令牌；已启用二步验证的账户只返回挑战令牌，需调用 /auth/login/2fa 完成登录
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -29,6 +43,8 @@ func NewAuthController(db *gorm.DB) *AuthController {
 // @Success 200 {object} models.LoginResponse "登录成功，返回访问令牌和用户信息"
 // @Failure 400 {object} map[string]string "请求参数错误"
 // @Failure 401 {object} map[string]string "登录凭据无效"
+// @Failure 403 {object} map[string]string "邮箱尚未验证"
+// @Failure 429 {object} map[string]string "失败次数过多，账户被临时锁定或需要等待后重试"
 // @Router /auth/login [post]
 func (c *AuthController) Login(ctx *gin.Context) {
 	var req models.LoginRequest
@@ -37,18 +53,67 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
-	loginResponse, err := c.userService.Login(&req)
+	loginResponse, err := c.userService.Login(&req, ctx.ClientIP())
 	if err != nil {
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			abortThrottled(ctx, throttled)
+			return
+		}
+		if errors.Is(err, services.ErrEmailNotVerified) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
+	c.mergeGuestCart(req.CartToken, loginResponse)
+
+	ctx.JSON(http.StatusOK, loginResponse)
+}
+
+// LoginTwoFactor godoc
+// @Summary 二步验证登录
+// @Description 登录第二步：提交第一步返回的挑战令牌和验证器应用中的验证码（或一次性恢复码），成功后返回访问令牌
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param login body models.TwoFactorLoginRequest true "挑战令牌和验证码"
+// @Success 200 {object} models.LoginResponse "登录成功，返回访问令牌和用户信息"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "挑战令牌无效或验证码错误"
+// @Failure 429 {object} map[string]string "失败次数过多，账户被临时锁定或需要等待后重试"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /auth/login/2fa [post]
+func (c *AuthController) LoginTwoFactor(ctx *gin.Context) {
+	var req models.TwoFactorLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loginResponse, err := c.userService.LoginWithTwoFactor(&req, ctx.ClientIP())
+	if err != nil {
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			abortThrottled(ctx, throttled)
+			return
+		}
+		if errors.Is(err, services.ErrInvalidTwoFactorChallenge) || errors.Is(err, services.ErrInvalidTwoFactorCode) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.mergeGuestCart(req.CartToken, loginResponse)
 
 	ctx.JSON(http.StatusOK, loginResponse)
 }
 
 // Register godoc
 // @Summary 用户注册
-// @Description 注册新用户账户，创建成功后需要使用登录接口获取访问令牌
+// @Description 注册新用户账户，新账户的邮箱处于未验证状态并会收到验证邮件，创建成功后需要使用登录接口获取访问令牌
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -64,10 +129,16 @@ func (c *AuthController) Register(ctx *gin.Context) {
 		return
 	}
 
-	// 普通注册只能创建普通用户
-	req.Role = "user"
+	// 普通注册只能创建普通用户，并加入默认组织
+	req.Role = models.RoleUser
+
+	org, err := c.organizationService.GetDefault()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	user, err := c.userService.CreateUser(&req)
+	user, err := c.userService.ForTenant(org.ID).CreateUser(&req)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -78,3 +149,233 @@ func (c *AuthController) Register(ctx *gin.Context) {
 		"user_id": user.ID,
 	})
 }
+
+// Refresh godoc
+// @Summary 刷新访问令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌立即失效；重复使用已轮换的刷新令牌会撤销该登录会话的所有刷新令牌
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refresh body models.RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} models.TokenResponse "刷新成功，返回新的令牌对"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "刷新令牌无效、已过期或被重复使用"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /auth/refresh [post]
+func (c *AuthController) Refresh(ctx *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := c.tokenService.Refresh(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenReused) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// Logout godoc
+// @Summary 用户登出
+// @Description 撤销当前访问令牌；可同时撤销指定的刷新令牌，或登出所有设备（撤销该用户的全部令牌）
+// @Tags auth
+// @Accept json
+// @Security ApiKeyAuth
+// @Param logout body models.LogoutRequest false "登出选项"
+// @Success 204 "登出成功，无返回内容"
+// @Failure 400 {object} map[string]string "请求参数错误或刷新令牌无效"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /auth/logout [post]
+func (c *AuthController) Logout(ctx *gin.Context) {
+	var req models.LogoutRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	value, exists := ctx.Get("claims")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	claims := value.(*utils.Claims)
+
+	if req.AllDevices {
+		if err := c.revocationService.RevokeAllForUser(claims.UserID); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	if req.RefreshToken != "" {
+		if err := c.tokenService.RevokeUserRefreshToken(claims.UserID, req.RefreshToken); err != nil {
+			if errors.Is(err, services.ErrInvalidRefreshToken) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := c.revocationService.RevokeToken(claims); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// JWKS 返回验证访问令牌所需的公钥集合（JWK Set），供其他服务在不共享密钥的情况下验证本服务签发的令牌
+func (c *AuthController) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, utils.PublicJWKS())
+}
+
+// ForgotPassword godoc
+// @Summary 忘记密码
+// @Description 向邮箱发送一次性密码重置链接；无论邮箱是否已注册都返回成功，避免泄露账户信息
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ForgotPasswordRequest true "注册邮箱"
+// @Success 200 {object} models.SuccessResponse "请求已受理"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /auth/forgot-password [post]
+func (c *AuthController) ForgotPassword(ctx *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.RequestPasswordReset(req.Email); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "If the email is registered, a password reset link has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary 重置密码
+// @Description 使用邮件中的一次性重置令牌设置新密码，重置成功后该用户的全部令牌失效
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordRequest true "重置令牌和新密码"
+// @Success 200 {object} models.SuccessResponse "重置成功"
+// @Failure 400 {object} map[string]string "请求参数错误或重置令牌无效、已过期、已使用"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /auth/reset-password [post]
+func (c *AuthController) ResetPassword(ctx *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.ResetPassword(&req); err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.SuccessResponse{Message: "Password has been reset"})
+}
+
+// VerifyEmail godoc
+// @Summary 验证邮箱
+// @Description 打开验证邮件中的签名链接完成邮箱验证
+// @Tags auth
+// @Produce json
+// @Param token query string true "邮件中的验证令牌"
+// @Success 200 {object} models.SuccessResponse "验证成功"
+// @Failure 400 {object} map[string]string "验证令牌无效或已过期"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /auth/verify-email [get]
+func (c *AuthController) VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	if _, err := c.userService.VerifyEmail(token); err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.SuccessResponse{Message: "Email verified successfully"})
+}
+
+// ResendVerification godoc
+// @Summary 重新发送验证邮件
+// @Description 向未验证的邮箱重新发送验证链接；无论邮箱是否存在都返回成功，避免泄露账户信息
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ResendVerificationRequest true "注册邮箱"
+// @Success 200 {object} models.SuccessResponse "请求已受理"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /auth/resend-verification [post]
+func (c *AuthController) ResendVerification(ctx *gin.Context) {
+	var req models.ResendVerificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.ResendVerificationEmail(req.Email); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "If the email is registered and unverified, a verification link has been sent",
+	})
+}
+
+// abortThrottled 返回 429 并通过 Retry-After 告知客户端需要等待的秒数
+func abortThrottled(ctx *gin.Context, err *services.LoginThrottledError) {
+	retryAfter := int(math.Ceil(err.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+	ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+}
+
+// mergeGuestCart 登录成功后把匿名购物车合并到用户的购物车。合并失败不影响登录，
+// 需要二步验证时在第二步完成后合并
+func (c *AuthController) mergeGuestCart(cartToken string, resp *models.LoginResponse) {
+	if cartToken == "" || resp.User == nil || resp.Token == "" {
+		return
+	}
+	if _, err := c.cartService.MergeGuestCartOnLogin(cartToken, resp.User.ID); err != nil {
+		log.Printf("Failed to merge guest cart for user %d: %v", resp.User.ID, err)
+	}
+}
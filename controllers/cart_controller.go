@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"errors"
+	"go-webapi-example/config"
+	"go-webapi-example/models"
+	"go-webapi-example/money"
+	"go-webapi-example/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CartController struct {
+	cartService *services.CartService
+}
+
+func NewCartController(db *gorm.DB, cfg *config.Config) *CartController {
+	return &CartController{
+		cartService: services.NewCartService(db, cfg),
+	}
+}
+
+// CreateGuestCart godoc
+// @Summary 创建匿名购物车
+// @Description 未登录的访客创建购物车，不需要认证，组织通过 X-Org-ID 请求头指定，且组织必须开放匿名店面（public_storefront）。
+// @Description 同一 IP 创建过于频繁时返回 429。返回的 token 只展示一次，
+// @Description 之后通过 X-Cart-Token 请求头访问 /guest-cart，登录时提交 cart_token 合并到用户的购物车
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param X-Org-ID header int true "组织ID"
+// @Param cart body models.CreateCartRequest false "购物车货币"
+// @Success 201 {object} models.Cart "创建成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 404 {object} map[string]string "组织不存在或未开放匿名店面"
+// @Failure 429 {object} map[string]string "创建过于频繁"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /guest-cart [post]
+func (c *CartController) CreateGuestCart(ctx *gin.Context) {
+	var req models.CreateCartRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	cart, err := c.cartService.ForTenant(ctx.GetUint("orgID")).CreateGuestCart(&req, ctx.ClientIP())
+	if err != nil {
+		cartErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, cart)
+}
+
+// GetCart godoc
+// @Summary 获取购物车
+// @Description 返回购物车，并按实时价格和可售库存重新校验每个行项目：已删除的产品（变体）标记为 unavailable，
+// @Description 库存不足标记为 insufficient_stock，价格变化时更新为实时价格并标记为 price_changed。
+// @Description /cart 为当前用户的购物车（不存在时自动创建）；/guest-cart 为匿名购物车，需要 X-Org-ID 和 X-Cart-Token 请求头
+// @Tags cart
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param X-Cart-Token header string false "匿名购物车令牌（仅 /guest-cart）"
+// @Success 200 {object} models.Cart "获取成功"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "匿名购物车不存在或已过期"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /cart [get]
+// @Router /guest-cart [get]
+func (c *CartController) GetCart(ctx *gin.Context) {
+	cart, err := c.cartService.ForTenant(ctx.GetUint("orgID")).GetCart(cartOwner(ctx))
+	if err != nil {
+		cartErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// AddCartItem godoc
+// @Summary 加入购物车
+// @Description 购物车中已有同一产品（变体）时累加数量，有变体的产品必须指定 variant_id，数量不能超过可售库存。
+// @Description 每个购物车最多 100 个行项目
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param X-Cart-Token header string false "匿名购物车令牌（仅 /guest-cart）"
+// @Param item body models.AddCartItemRequest true "产品和数量"
+// @Success 200 {object} models.Cart "加入成功，返回购物车"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "产品或匿名购物车不存在"
+// @Failure 409 {object} map[string]string "可售库存不足或购物车已满"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /cart/items [post]
+// @Router /guest-cart/items [post]
+func (c *CartController) AddCartItem(ctx *gin.Context) {
+	var req models.AddCartItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cart, err := c.cartService.ForTenant(ctx.GetUint("orgID")).AddItem(cartOwner(ctx), &req)
+	if err != nil {
+		cartErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// UpdateCartItem godoc
+// @Summary 修改购物车行项目数量
+// @Description 数量不能超过可售库存
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param X-Cart-Token header string false "匿名购物车令牌（仅 /guest-cart）"
+// @Param itemId path int true "行项目ID"
+// @Param item body models.UpdateCartItemRequest true "新数量"
+// @Success 200 {object} models.Cart "修改成功，返回购物车"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "行项目或匿名购物车不存在"
+// @Failure 409 {object} map[string]string "可售库存不足"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /cart/items/{itemId} [put]
+// @Router /guest-cart/items/{itemId} [put]
+func (c *CartController) UpdateCartItem(ctx *gin.Context) {
+	itemID, err := strconv.ParseUint(ctx.Param("itemId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cart item ID"})
+		return
+	}
+
+	var req models.UpdateCartItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cart, err := c.cartService.ForTenant(ctx.GetUint("orgID")).UpdateItem(cartOwner(ctx), uint(itemID), &req)
+	if err != nil {
+		cartErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// RemoveCartItem godoc
+// @Summary 删除购物车行项目
+// @Tags cart
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param X-Cart-Token header string false "匿名购物车令牌（仅 /guest-cart）"
+// @Param itemId path int true "行项目ID"
+// @Success 200 {object} models.Cart "删除成功，返回购物车"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "行项目或匿名购物车不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /cart/items/{itemId} [delete]
+// @Router /guest-cart/items/{itemId} [delete]
+func (c *CartController) RemoveCartItem(ctx *gin.Context) {
+	itemID, err := strconv.ParseUint(ctx.Param("itemId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cart item ID"})
+		return
+	}
+
+	cart, err := c.cartService.ForTenant(ctx.GetUint("orgID")).RemoveItem(cartOwner(ctx), uint(itemID))
+	if err != nil {
+		cartErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// ClearCart godoc
+// @Summary 清空购物车
+// @Tags cart
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param X-Cart-Token header string false "匿名购物车令牌（仅 /guest-cart）"
+// @Success 200 {object} models.Cart "清空成功，返回购物车"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "匿名购物车不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /cart [delete]
+// @Router /guest-cart [delete]
+func (c *CartController) ClearCart(ctx *gin.Context) {
+	cart, err := c.cartService.ForTenant(ctx.GetUint("orgID")).Clear(cartOwner(ctx))
+	if err != nil {
+		cartErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// MergeCart godoc
+// @Summary 合并匿名购物车
+// @Description 把当前组织中的匿名购物车合并到当前用户的购物车：相同产品（变体）累加数量，其余行项目移入，然后删除匿名购物车。
+// @Description 登录接口提交 cart_token 时会自动合并
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param merge body models.MergeCartRequest true "匿名购物车令牌"
+// @Success 200 {object} models.Cart "合并成功，返回购物车"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "匿名购物车不存在或已过期"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /cart/merge [post]
+func (c *CartController) MergeCart(ctx *gin.Context) {
+	var req models.MergeCartRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cart, err := c.cartService.ForTenant(ctx.GetUint("orgID")).MergeGuestCart(req.CartToken, ctx.GetUint("userID"))
+	if err != nil {
+		cartErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+// cartOwner 登录用户使用自己的购物车，匿名访问使用 X-Cart-Token 对应的购物车
+func cartOwner(ctx *gin.Context) services.CartOwner {
+	return services.CartOwner{UserID: ctx.GetUint("userID"), Token: ctx.GetHeader("X-Cart-Token")}
+}
+
+func cartErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCartNotFound), errors.Is(err, services.ErrCartItemNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCartFull):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTooManyGuestCarts):
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, money.ErrUnknownCurrency), errors.Is(err, money.ErrNoRate):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		productErrorResponse(ctx, err)
+	}
+}
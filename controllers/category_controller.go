@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"errors"
+	"go-webapi-example/models"
+	"go-webapi-example/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CategoryController struct {
+	categoryService *services.CategoryService
+}
+
+func NewCategoryController(db *gorm.DB) *CategoryController {
+	return &CategoryController{
+		categoryService: services.NewCategoryService(db),
+	}
+}
+
+// GetCategories godoc
+// @Summary 获取分类树
+// @Description 返回当前组织的完整分类树，同级分类按名称排序
+// @Tags categories
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {array} models.Category "获取成功"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /categories [get]
+func (c *CategoryController) GetCategories(ctx *gin.Context) {
+	tree, err := c.categoryService.ForTenant(ctx.GetUint("orgID")).GetTree()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tree)
+}
+
+// GetCategory godoc
+// @Summary 获取分类详情
+// @Description 返回分类信息、面包屑和直接下级分类。分类下的产品（含子孙分类）通过 GET /products?category_id= 查询
+// @Tags categories
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "分类ID"
+// @Success 200 {object} models.Category "获取成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "分类不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /categories/{id} [get]
+func (c *CategoryController) GetCategory(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	category, err := c.categoryService.ForTenant(ctx.GetUint("orgID")).GetCategoryByID(uint(id))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, category)
+}
+
+// CreateCategory godoc
+// @Summary 创建分类（管理员）
+// @Description 创建根分类或指定上级分类的子分类。需要 categories:manage 权限
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param category body models.CreateCategoryRequest true "分类信息"
+// @Success 201 {object} models.Category "创建成功"
+// @Failure 400 {object} map[string]string "请求参数错误或上级分类不存在"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 409 {object} map[string]string "分类标识已存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/categories [post]
+func (c *CategoryController) CreateCategory(ctx *gin.Context) {
+	var req models.CreateCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := c.categoryService.ForTenant(ctx.GetUint("orgID")).CreateCategory(&req)
+	if err != nil {
+		ctx.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategory godoc
+// @Summary 更新分类（管理员）
+// @Description 修改分类名称、标识或移动到新的上级分类（parent_id 为 0 移动到根级），子孙分类随之移动。需要 categories:manage 权限
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "分类ID"
+// @Param category body models.UpdateCategoryRequest true "分类更新信息"
+// @Success 200 {object} models.Category "更新成功"
+// @Failure 400 {object} map[string]string "请求参数错误、上级分类不存在或移动到自身的子孙分类下"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "分类不存在"
+// @Failure 409 {object} map[string]string "分类标识已存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/categories/{id} [put]
+func (c *CategoryController) UpdateCategory(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var req models.UpdateCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := c.categoryService.ForTenant(ctx.GetUint("orgID")).UpdateCategory(uint(id), &req)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return
+		}
+		ctx.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, category)
+}
+
+// DeleteCategory godoc
+// @Summary 删除分类（管理员）
+// @Description 删除没有下级分类的分类，并解除与产品的关联。需要 categories:manage 权限
+// @Tags categories
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "分类ID"
+// @Success 204 "删除成功，无返回内容"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "分类不存在"
+// @Failure 409 {object} map[string]string "分类仍有下级分类"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/categories/{id} [delete]
+func (c *CategoryController) DeleteCategory(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	if err := c.categoryService.ForTenant(ctx.GetUint("orgID")).DeleteCategory(uint(id)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return
+		}
+		ctx.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func categoryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrUnknownCategory), errors.Is(err, services.ErrCategoryCycle):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrCategoryExists), errors.Is(err, services.ErrCategoryHasChildren):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
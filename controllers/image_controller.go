@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"errors"
+	"go-webapi-example/config"
+	"go-webapi-example/middleware"
+	"go-webapi-example/models"
+	"go-webapi-example/services"
+	"go-webapi-example/storage"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// multipartOverhead 请求体上限在图片大小之外为 multipart 边界和其他表单字段预留的空间
+const multipartOverhead = 64 << 10
+
+type ImageController struct {
+	imageService *services.ImageService
+	cfg          *config.Config
+}
+
+func NewImageController(db *gorm.DB, cfg *config.Config, store storage.Storage) *ImageController {
+	return &ImageController{
+		imageService: services.NewImageService(db, store, cfg),
+		cfg:          cfg,
+	}
+}
+
+// GetProductImages godoc
+// @Summary 获取产品图片
+// @Description 按顺序返回产品的全部图片，包括原图和缩略图地址
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Success 200 {array} models.ProductImage "获取成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/images [get]
+func (c *ImageController) GetProductImages(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	images, err := c.imageService.ForTenant(ctx.GetUint("orgID")).ListImages(uint(id))
+	if err != nil {
+		imageErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, images)
+}
+
+// UploadProductImage godoc
+// @Summary 上传产品图片
+// @Description 以 multipart/form-data 上传一张图片（字段名 file），支持 JPEG、PNG 和 GIF，类型根据文件内容判断。
+// @Description 服务端生成缩略图；新图片排在最后，产品的第一张图片或 is_primary=true 时设为主图。只有创建者或拥有 products:manage 权限的管理员可以上传
+// @Tags products
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param file formData file true "图片文件"
+// @Param is_primary formData bool false "是否设为主图"
+// @Success 201 {object} models.ProductImage "上传成功"
+// @Failure 400 {object} map[string]string "请求参数错误或图片无法解析"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 413 {object} map[string]string "图片超过大小限制"
+// @Failure 415 {object} map[string]string "不支持的图片类型"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/images [post]
+func (c *ImageController) UploadProductImage(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	// 限制整个请求体的大小，超出时读取请求体会返回 *http.MaxBytesError
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, int64(c.cfg.ImageMaxBytes)+multipartOverhead)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrImageTooLarge.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "A file field named \"file\" is required"})
+		return
+	}
+	if fileHeader.Size > int64(c.cfg.ImageMaxBytes) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrImageTooLarge.Error()})
+		return
+	}
+
+	primary := false
+	if raw := ctx.PostForm("is_primary"); raw != "" {
+		if primary, err = strconv.ParseBool(raw); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "is_primary must be a boolean"})
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, int64(c.cfg.ImageMaxBytes)+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	image, err := c.imageService.ForTenant(ctx.GetUint("orgID")).
+		UploadImage(ctx.Request.Context(), uint(id), data, primary, middleware.CurrentActor(ctx))
+	if err != nil {
+		imageErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, image)
+}
+
+// ReorderProductImages godoc
+// @Summary 调整产品图片顺序
+// @Description 按 image_ids 的顺序重新排列产品图片，必须包含产品的全部图片且不能重复。只有创建者或拥有 products:manage 权限的管理员可以修改
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param order body models.ReorderProductImagesRequest true "新的图片顺序"
+// @Success 200 {array} models.ProductImage "调整成功，返回排序后的全部图片"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/images/order [put]
+func (c *ImageController) ReorderProductImages(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req models.ReorderProductImagesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	images, err := c.imageService.ForTenant(ctx.GetUint("orgID")).ReorderImages(uint(id), req.ImageIDs, middleware.CurrentActor(ctx))
+	if err != nil {
+		imageErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, images)
+}
+
+// SetPrimaryProductImage godoc
+// @Summary 设置产品主图
+// @Description 将指定图片设为产品主图，原主图取消主图标记。只有创建者或拥有 products:manage 权限的管理员可以修改
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param imageId path int true "图片ID"
+// @Success 200 {object} models.ProductImage "设置成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品或图片不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/images/{imageId}/primary [put]
+func (c *ImageController) SetPrimaryProductImage(ctx *gin.Context) {
+	id, imageID, ok := parseImagePath(ctx)
+	if !ok {
+		return
+	}
+
+	image, err := c.imageService.ForTenant(ctx.GetUint("orgID")).SetPrimary(id, imageID, middleware.CurrentActor(ctx))
+	if err != nil {
+		imageErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, image)
+}
+
+// DeleteProductImage godoc
+// @Summary 删除产品图片
+// @Description 删除产品图片及其缩略图，删除主图时排在最前的图片成为新的主图。只有创建者或拥有 products:manage 权限的管理员可以删除
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param imageId path int true "图片ID"
+// @Success 200 {object} map[string]string "删除成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品或图片不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/images/{imageId} [delete]
+func (c *ImageController) DeleteProductImage(ctx *gin.Context) {
+	id, imageID, ok := parseImagePath(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.imageService.ForTenant(ctx.GetUint("orgID")).DeleteImage(id, imageID, middleware.CurrentActor(ctx)); err != nil {
+		imageErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Image deleted successfully"})
+}
+
+func parseImagePath(ctx *gin.Context) (productID, imageID uint, ok bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return 0, 0, false
+	}
+	imgID, err := strconv.ParseUint(ctx.Param("imageId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image ID"})
+		return 0, 0, false
+	}
+	return uint(id), uint(imgID), true
+}
+
+func imageErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrImageNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
+	case errors.Is(err, services.ErrImageTooLarge):
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnsupportedImage):
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidImage), errors.Is(err, services.ErrImageOrder):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		productErrorResponse(ctx, err)
+	}
+}
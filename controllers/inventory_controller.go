@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"go-webapi-example/listing"
+	"go-webapi-example/middleware"
+	"go-webapi-example/models"
+	"go-webapi-example/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type InventoryController struct {
+	inventoryService *services.InventoryService
+}
+
+func NewInventoryController(db *gorm.DB) *InventoryController {
+	return &InventoryController{
+		inventoryService: services.NewInventoryService(db),
+	}
+}
+
+// IncrementStock godoc
+// @Summary 增加库存
+// @Description 原子地增加产品库存并记录流水，类型为 receive（入库）、return（退货）或 adjust（盘盈）。
+// @Description 有变体的产品必须指定 variant_id。只有创建者或拥有 products:manage 权限的管理员可以操作
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param adjustment body models.StockAdjustmentRequest true "数量、类型和原因"
+// @Success 201 {object} models.StockMovement "增加成功，返回库存流水"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/stock/increment [post]
+func (c *InventoryController) IncrementStock(ctx *gin.Context) {
+	c.adjustStock(ctx, c.inventoryService.ForTenant(ctx.GetUint("orgID")).Increment)
+}
+
+// DecrementStock godoc
+// @Summary 扣减库存
+// @Description 原子地扣减产品库存并记录流水，类型为 sell（售出）或 adjust（盘亏），库存不足时返回 409 且不做任何变动。
+// @Description 有变体的产品必须指定 variant_id。只有创建者或拥有 products:manage 权限的管理员可以操作
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param adjustment body models.StockAdjustmentRequest true "数量、类型和原因"
+// @Success 201 {object} models.StockMovement "扣减成功，返回库存流水"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 409 {object} map[string]string "库存不足"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/stock/decrement [post]
+func (c *InventoryController) DecrementStock(ctx *gin.Context) {
+	c.adjustStock(ctx, c.inventoryService.ForTenant(ctx.GetUint("orgID")).Decrement)
+}
+
+// GetStockHistory godoc
+// @Summary 获取库存流水
+// @Description 分页获取产品的库存变动记录，默认按时间倒序。分页参数与产品列表相同
+// @Tags inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param page query int false "页码，从 1 开始"
+// @Param page_size query int false "每页数量，默认 20"
+// @Param limit query int false "每页数量（与 offset 配合使用）"
+// @Param offset query int false "偏移量"
+// @Param cursor query string false "游标分页：首页传空值，之后传 next_cursor 或 prev_cursor"
+// @Param sort query string false "排序字段（id、created_at），默认 -created_at"
+// @Param type query string false "变动类型：receive、sell、adjust、return"
+// @Param variant_id query int false "变体ID"
+// @Param actor_id query int false "操作用户ID"
+// @Param created_from query string false "变动时间起（RFC3339 或 YYYY-MM-DD）"
+// @Param created_to query string false "变动时间止（RFC3339 或 YYYY-MM-DD）"
+// @Success 200 {object} models.StockMovementListResponse "获取成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/stock/history [get]
+func (c *InventoryController) GetStockHistory(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	params, err := listing.Parse(ctx.Request.URL.Query(), services.StockMovementListSpec)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := c.inventoryService.ForTenant(ctx.GetUint("orgID")).History(uint(id), params)
+	if err != nil {
+		productErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.StockMovementListResponse{
+		Data:       page.Items,
+		Pagination: newPagination(ctx, params, page),
+	})
+}
+
+func (c *InventoryController) adjustStock(ctx *gin.Context, apply func(uint, *models.StockAdjustmentRequest, *services.Actor) (*models.StockMovement, error)) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req models.StockAdjustmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movement, err := apply(uint(id), &req, middleware.CurrentActor(ctx))
+	if err != nil {
+		productErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, movement)
+}
@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"errors"
+	"go-webapi-example/config"
+	"go-webapi-example/listing"
+	"go-webapi-example/middleware"
+	"go-webapi-example/models"
+	"go-webapi-example/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type OrderController struct {
+	orderService *services.OrderService
+}
+
+func NewOrderController(db *gorm.DB, cfg *config.Config) *OrderController {
+	return &OrderController{
+		orderService: services.NewOrderService(db, cfg),
+	}
+}
+
+// CreateOrder godoc
+// @Summary 直接下单
+// @Description 不经过购物车直接下单。按实时价格快照产品名称和单价，并在同一事务中扣减库存，任一行库存不足时整个订单失败。
+// @Description 有变体的产品必须指定 variant_id，同一产品（变体）的多行合并为一行。订单超过 ORDER_PENDING_TTL 未支付时自动取消并退回库存
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param order body models.CreateOrderRequest true "订单行项目"
+// @Success 201 {object} models.Order "下单成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 409 {object} map[string]string "可售库存不足或待支付订单过多"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /orders [post]
+func (c *OrderController) CreateOrder(ctx *gin.Context) {
+	var req models.CreateOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := c.orderService.ForTenant(ctx.GetUint("orgID")).CreateOrder(&req, middleware.CurrentActor(ctx))
+	if err != nil {
+		checkoutErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, order)
+}
+
+// Checkout godoc
+// @Summary 购物车下单
+// @Description 将当前用户的购物车下单，成功后清空购物车。单价与最近一次读取购物车时不同时返回 409，
+// @Description 重新获取购物车确认新价格后再下单
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param checkout body models.CheckoutRequest false "买家备注"
+// @Success 201 {object} models.Order "下单成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 409 {object} map[string]string "购物车为空、价格已变化、可售库存不足或待支付订单过多"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /orders/checkout [post]
+func (c *OrderController) Checkout(ctx *gin.Context) {
+	var req models.CheckoutRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	order, err := c.orderService.ForTenant(ctx.GetUint("orgID")).Checkout(&req, middleware.CurrentActor(ctx))
+	if err != nil {
+		checkoutErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, order)
+}
+
+// GetMyOrders godoc
+// @Summary 获取我的订单
+// @Description 分页获取当前用户的订单，包含行项目
+// @Tags orders
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param page query int false "页码（从 1 开始）"
+// @Param page_size query int false "每页数量，默认 20，最大 100"
+// @Param limit query int false "每页数量（与 offset 配合使用）"
+// @Param offset query int false "偏移量"
+// @Param cursor query string false "游标分页：首页传空值，之后传 next_cursor 或 prev_cursor"
+// @Param sort query string false "排序字段（id、created_at、updated_at），默认 -created_at"
+// @Param status query string false "订单状态：pending、paid、fulfilled、completed、cancelled、refunded"
+// @Param created_from query string false "下单时间起（RFC3339 或 YYYY-MM-DD）"
+// @Param created_to query string false "下单时间止（RFC3339 或 YYYY-MM-DD）"
+// @Success 200 {object} models.OrderListResponse "获取成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /orders [get]
+func (c *OrderController) GetMyOrders(ctx *gin.Context) {
+	c.listOrders(ctx, middleware.CurrentActor(ctx).UserID)
+}
+
+// GetOrders godoc
+// @Summary 获取全部订单（管理员）
+// @Description 分页获取组织内所有用户的订单，需要 orders:manage 权限
+// @Tags orders
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param page query int false "页码（从 1 开始）"
+// @Param page_size query int false "每页数量，默认 20，最大 100"
+// @Param limit query int false "每页数量（与 offset 配合使用）"
+// @Param offset query int false "偏移量"
+// @Param cursor query string false "游标分页：首页传空值，之后传 next_cursor 或 prev_cursor"
+// @Param sort query string false "排序字段（id、created_at、updated_at），默认 -created_at"
+// @Param status query string false "订单状态：pending、paid、fulfilled、completed、cancelled、refunded"
+// @Param user_id query int false "下单用户ID"
+// @Param created_from query string false "下单时间起（RFC3339 或 YYYY-MM-DD）"
+// @Param created_to query string false "下单时间止（RFC3339 或 YYYY-MM-DD）"
+// @Success 200 {object} models.OrderListResponse "获取成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/orders [get]
+func (c *OrderController) GetOrders(ctx *gin.Context) {
+	c.listOrders(ctx, 0)
+}
+
+// GetOrder godoc
+// @Summary 获取订单详情
+// @Description 获取订单、行项目和状态变更记录。只有下单用户或拥有 orders:manage 权限的管理员可以查看
+// @Tags orders
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "订单ID"
+// @Success 200 {object} models.Order "获取成功"
+// @Failure 400 {object} map[string]string "无效的订单ID"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "订单不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /orders/{id} [get]
+func (c *OrderController) GetOrder(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := c.orderService.ForTenant(ctx.GetUint("orgID")).GetOrder(uint(id), middleware.CurrentActor(ctx))
+	if err != nil {
+		orderErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+// CancelOrder godoc
+// @Summary 取消订单
+// @Description 下单用户取消自己待支付（pending）的订单，库存退回
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "订单ID"
+// @Success 200 {object} models.Order "取消成功"
+// @Failure 400 {object} map[string]string "无效的订单ID"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "订单不存在"
+// @Failure 409 {object} map[string]string "订单当前状态不能取消"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /orders/{id}/cancel [post]
+func (c *OrderController) CancelOrder(ctx *gin.Context) {
+	c.transition(ctx, &models.OrderTransitionRequest{Status: models.OrderCancelled})
+}
+
+// TransitionOrder godoc
+// @Summary 推进订单状态（管理员）
+// @Description 按状态机推进订单：pending → paid → fulfilled → completed，pending 可取消，paid、fulfilled、completed 可退款。
+// @Description 取消和发货前退款时库存退回，发货后退款时由 restock 决定是否退回。每次变更都记入订单的状态变更记录
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "订单ID"
+// @Param transition body models.OrderTransitionRequest true "目标状态"
+// @Success 200 {object} models.Order "变更成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "订单不存在"
+// @Failure 409 {object} map[string]string "不允许的状态变更"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/orders/{id}/transitions [post]
+func (c *OrderController) TransitionOrder(ctx *gin.Context) {
+	var req models.OrderTransitionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.transition(ctx, &req)
+}
+
+func (c *OrderController) transition(ctx *gin.Context, req *models.OrderTransitionRequest) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := c.orderService.ForTenant(ctx.GetUint("orgID")).Transition(uint(id), req, middleware.CurrentActor(ctx))
+	if err != nil {
+		orderErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+func (c *OrderController) listOrders(ctx *gin.Context, userID uint) {
+	params, err := listing.Parse(ctx.Request.URL.Query(), services.OrderListSpec)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := c.orderService.ForTenant(ctx.GetUint("orgID")).ListOrders(params, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OrderListResponse{
+		Data:       page.Items,
+		Pagination: newPagination(ctx, params, page),
+	})
+}
+
+func orderErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+	case errors.Is(err, services.ErrInvalidTransition):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		productErrorResponse(ctx, err)
+	}
+}
+
+// checkoutErrorResponse 下单时找不到的记录是产品或变体
+func checkoutErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCartEmpty), errors.Is(err, services.ErrCartChanged), errors.Is(err, services.ErrTooManyPending):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		cartErrorResponse(ctx, err)
+	}
+}
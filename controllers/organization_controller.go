@@ -0,0 +1,326 @@
+package controllers
+
+import (
+	"errors"
+	"go-webapi-example/middleware"
+	"go-webapi-example/models"
+	"go-webapi-example/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type OrganizationController struct {
+	organizationService *services.OrganizationService
+}
+
+func NewOrganizationController(db *gorm.DB) *OrganizationController {
+	return &OrganizationController{
+		organizationService: services.NewOrganizationService(db),
+	}
+}
+
+// GetOrganizations godoc
+// @Summary 获取组织列表
+// @Description 返回当前用户加入的组织；拥有 organizations:manage 权限时返回全部组织
+// @Tags organizations
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {array} models.Organization "获取成功"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /organizations [get]
+func (c *OrganizationController) GetOrganizations(ctx *gin.Context) {
+	var (
+		orgs []models.Organization
+		err  error
+	)
+	if middleware.HasPermission(ctx, models.PermOrgsManage) {
+		orgs, err = c.organizationService.GetAllOrganizations()
+	} else {
+		orgs, err = c.organizationService.GetUserOrganizations(ctx.GetUint("userID"))
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orgs)
+}
+
+// CreateOrganization godoc
+// @Summary 创建组织
+// @Description 创建新的组织（租户），创建者成为组织的 owner。需要 organizations:manage 权限
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param organization body models.CreateOrganizationRequest true "组织信息"
+// @Success 201 {object} models.Organization "创建成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 409 {object} map[string]string "组织标识已存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /organizations [post]
+func (c *OrganizationController) CreateOrganization(ctx *gin.Context) {
+	var req models.CreateOrganizationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	org, err := c.organizationService.CreateOrganization(&req, ctx.GetUint("userID"))
+	if err != nil {
+		ctx.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, org)
+}
+
+// UpdateOrganizationSettings godoc
+// @Summary 修改组织设置
+// @Description 修改组织设置，只有组织 owner 或拥有 organizations:manage 权限的用户可以操作。
+// @Description public_storefront 为 true 时未登录访客可以通过 /guest-cart 使用匿名购物车，并看到购物车中产品的名称、价格和可售库存
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "组织ID"
+// @Param settings body models.UpdateOrganizationSettingsRequest true "组织设置"
+// @Success 200 {object} models.Organization "修改成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /organizations/{id}/settings [put]
+func (c *OrganizationController) UpdateOrganizationSettings(ctx *gin.Context) {
+	orgID, orgRole, ok := c.resolveOrganization(ctx, false)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateOrganizationSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	org, err := c.organizationService.UpdateSettings(orgID, &req, middleware.CurrentActor(ctx), orgRole)
+	if err != nil {
+		ctx.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, org)
+}
+
+// GetMembers godoc
+// @Summary 获取组织成员
+// @Description 返回组织的全部成员及其组织内角色，只有组织成员可以查看
+// @Tags organizations
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "组织ID"
+// @Success 200 {array} models.Membership "获取成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "不是该组织成员"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /organizations/{id}/members [get]
+func (c *OrganizationController) GetMembers(ctx *gin.Context) {
+	orgID, _, ok := c.resolveOrganization(ctx, false)
+	if !ok {
+		return
+	}
+
+	members, err := c.organizationService.GetMembers(orgID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, members)
+}
+
+// AddMember godoc
+// @Summary 添加组织成员
+// @Description 将已有用户加入组织。组织的 owner、admin 可以操作，授予 owner 角色需要 owner 本人操作。
+// @Description 只能添加与操作者同在某个组织中的用户（拥有 organizations:manage 权限时不限），响应只包含成员关系
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "组织ID"
+// @Param member body models.AddMemberRequest true "成员信息"
+// @Success 201 {object} models.Membership "添加成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "用户不存在或不可见"
+// @Failure 409 {object} map[string]string "用户已是组织成员"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /organizations/{id}/members [post]
+func (c *OrganizationController) AddMember(ctx *gin.Context) {
+	orgID, orgRole, ok := c.resolveOrganization(ctx, true)
+	if !ok {
+		return
+	}
+
+	var req models.AddMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	membership, err := c.organizationService.AddMember(orgID, &req, middleware.CurrentActor(ctx), orgRole)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, membership)
+}
+
+// UpdateMember godoc
+// @Summary 修改组织成员角色
+// @Description 修改成员的组织内角色。涉及 owner 的变更需要 owner 本人操作，最后一个 owner 不能降级
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "组织ID"
+// @Param userId path int true "用户ID"
+// @Param member body models.UpdateMemberRequest true "组织内角色"
+// @Success 200 {object} models.Membership "修改成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "用户不是组织成员"
+// @Failure 409 {object} map[string]string "不能降级最后一个 owner"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /organizations/{id}/members/{userId} [put]
+func (c *OrganizationController) UpdateMember(ctx *gin.Context) {
+	orgID, orgRole, ok := c.resolveOrganization(ctx, true)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.UpdateMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	membership, err := c.organizationService.UpdateMemberRole(orgID, uint(userID), req.Role, middleware.CurrentActor(ctx), orgRole)
+	if err != nil {
+		ctx.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, membership)
+}
+
+// RemoveMember godoc
+// @Summary 移除组织成员
+// @Description 将用户移出组织。移除 owner 需要 owner 本人操作，最后一个 owner 不能移除
+// @Tags organizations
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "组织ID"
+// @Param userId path int true "用户ID"
+// @Success 204 "移除成功，无返回内容"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "用户不是组织成员"
+// @Failure 409 {object} map[string]string "不能移除最后一个 owner"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /organizations/{id}/members/{userId} [delete]
+func (c *OrganizationController) RemoveMember(ctx *gin.Context) {
+	orgID, orgRole, ok := c.resolveOrganization(ctx, true)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := c.organizationService.RemoveMember(orgID, uint(userID), middleware.CurrentActor(ctx), orgRole); err != nil {
+		ctx.JSON(organizationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// resolveOrganization 解析路径中的组织ID并返回当前用户在该组织中的角色；
+// 不是成员（且没有 organizations:manage 权限）或 manage 为 true 但无权管理成员时直接返回错误响应
+func (c *OrganizationController) resolveOrganization(ctx *gin.Context, manage bool) (uint, string, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return 0, "", false
+	}
+	orgID := uint(id)
+	actor := middleware.CurrentActor(ctx)
+
+	var orgRole string
+	membership, err := c.organizationService.GetMembership(orgID, actor.UserID)
+	switch {
+	case err == nil:
+		orgRole = membership.Role
+	case errors.Is(err, gorm.ErrRecordNotFound) && actor.Can(models.PermOrgsManage):
+		if _, err := c.organizationService.GetOrganizationByID(orgID); err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+			return 0, "", false
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not a member of this organization"})
+		return 0, "", false
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return 0, "", false
+	}
+
+	if manage && !services.CanManageMembers(actor, orgRole) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only organization owners and admins can manage members"})
+		return 0, "", false
+	}
+	return orgID, orgRole, true
+}
+
+func organizationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNotMember):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrOrgOwnerRequired):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrOrganizationExists), errors.Is(err, services.ErrAlreadyMember), errors.Is(err, services.ErrLastOrgOwner):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"go-webapi-example/listing"
+	"go-webapi-example/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newPagination 根据列表参数和查询结果生成分页信息，链接基于当前请求地址
+func newPagination[T any](ctx *gin.Context, params *listing.Params, page *listing.Page[T]) models.Pagination {
+	next, prev := params.Links(ctx.Request.URL, page.Total, page.NextCursor, page.PrevCursor)
+	return models.Pagination{
+		Total:      page.Total,
+		Page:       params.Page(),
+		Limit:      params.Limit,
+		Offset:     params.Offset,
+		Next:       next,
+		Prev:       prev,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+}
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"errors"
+	"go-webapi-example/config"
+	"go-webapi-example/middleware"
+	"go-webapi-example/models"
+	"go-webapi-example/money"
+	"go-webapi-example/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PriceController struct {
+	priceService *services.PriceService
+}
+
+func NewPriceController(db *gorm.DB, cfg *config.Config) *PriceController {
+	return &PriceController{
+		priceService: services.NewPriceService(db, cfg),
+	}
+}
+
+// GetProductPrices godoc
+// @Summary 获取产品价格表
+// @Description 返回产品在其他货币下单独设定的价格，按货币代码排序
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Success 200 {array} models.ProductPrice "获取成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/prices [get]
+func (c *PriceController) GetProductPrices(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	prices, err := c.priceService.ForTenant(ctx.GetUint("orgID")).ListPrices(uint(id))
+	if err != nil {
+		priceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, prices)
+}
+
+// SetProductPrices godoc
+// @Summary 设置产品价格表
+// @Description 整体替换产品在其他货币下的价格，每种货币最多一条，不能包含产品自身的货币；
+// @Description 金额的小数位数不能超过货币允许的位数。只有创建者或管理员可以修改
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param prices body models.SetProductPricesRequest true "价格表"
+// @Success 200 {array} models.ProductPrice "设置成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/prices [put]
+func (c *PriceController) SetProductPrices(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req models.SetProductPricesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prices, err := c.priceService.ForTenant(ctx.GetUint("orgID")).SetPrices(uint(id), req.Prices, middleware.CurrentActor(ctx))
+	if err != nil {
+		priceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, prices)
+}
+
+// GetProductPriceQuote godoc
+// @Summary 获取产品在指定货币下的价格
+// @Description 与产品货币相同时返回产品价格（source=base），其次使用价格表（source=list），
+// @Description 价格表中没有该货币时按 EXCHANGE_RATES 配置的汇率换算（source=converted）
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param currency query string true "ISO 4217 货币代码，例如 EUR"
+// @Success 200 {object} models.PriceQuote "获取成功"
+// @Failure 400 {object} map[string]string "货币无效或没有配置汇率"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/price [get]
+func (c *PriceController) GetProductPriceQuote(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	quote, err := c.priceService.ForTenant(ctx.GetUint("orgID")).Quote(uint(id), ctx.Query("currency"))
+	if err != nil {
+		priceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quote)
+}
+
+func priceErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrDuplicateCurrency), errors.Is(err, services.ErrBaseCurrencyPrice),
+		errors.Is(err, money.ErrUnknownCurrency), errors.Is(err, money.ErrNoRate):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		productErrorResponse(ctx, err)
+	}
+}
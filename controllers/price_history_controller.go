@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"errors"
+	"go-webapi-example/config"
+	"go-webapi-example/listing"
+	"go-webapi-example/middleware"
+	"go-webapi-example/models"
+	"go-webapi-example/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PriceHistoryController struct {
+	priceHistoryService *services.PriceHistoryService
+}
+
+func NewPriceHistoryController(db *gorm.DB, cfg *config.Config) *PriceHistoryController {
+	return &PriceHistoryController{
+		priceHistoryService: services.NewPriceHistoryService(db, cfg),
+	}
+}
+
+// GetPriceHistory godoc
+// @Summary 获取价格变动记录
+// @Description 分页获取产品和变体的价格变动记录（变动前后价格、来源、操作用户和时间），默认按时间倒序。分页参数与产品列表相同
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param page query int false "页码，从 1 开始"
+// @Param page_size query int false "每页数量，默认 20"
+// @Param limit query int false "每页数量（与 offset 配合使用）"
+// @Param offset query int false "偏移量"
+// @Param cursor query string false "游标分页：首页传空值，之后传 next_cursor 或 prev_cursor"
+// @Param sort query string false "排序字段（id、created_at），默认 -created_at"
+// @Param variant_id query int false "变体ID"
+// @Param source query string false "来源：created、manual、scheduled、schedule_ended"
+// @Param actor_id query int false "操作用户ID"
+// @Param created_from query string false "变动时间起（RFC3339 或 YYYY-MM-DD）"
+// @Param created_to query string false "变动时间止（RFC3339 或 YYYY-MM-DD）"
+// @Success 200 {object} models.PriceChangeListResponse "获取成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/price-history [get]
+func (c *PriceHistoryController) GetPriceHistory(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	params, err := listing.Parse(ctx.Request.URL.Query(), services.PriceChangeListSpec)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := c.priceHistoryService.ForTenant(ctx.GetUint("orgID")).History(uint(id), params)
+	if err != nil {
+		productErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.PriceChangeListResponse{
+		Data:       page.Items,
+		Pagination: newPagination(ctx, params, page),
+	})
+}
+
+// GetScheduledPrices godoc
+// @Summary 获取定时价格
+// @Description 返回产品的全部定时价格（含已结束和已取消的），按开始时间排序
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Success 200 {array} models.ScheduledPrice "获取成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/scheduled-prices [get]
+func (c *PriceHistoryController) GetScheduledPrices(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	scheduled, err := c.priceHistoryService.ForTenant(ctx.GetUint("orgID")).ListScheduled(uint(id))
+	if err != nil {
+		productErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+// SchedulePrice godoc
+// @Summary 创建定时价格
+// @Description 在 starts_at 把产品价格改为指定价格，到达 ends_at 时恢复生效前的价格（生效期间价格被手动修改过时保留修改后的价格）；
+// @Description 不提交 ends_at 表示长期生效。后台任务每隔 PRICE_SCHEDULE_INTERVAL 应用到期的定时价格。
+// @Description 同一产品的定时价格时间窗口不能重叠，有变体的产品价格由变体汇总，不能定时修改
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param scheduled body models.SchedulePriceRequest true "价格和时间窗口"
+// @Success 201 {object} models.ScheduledPrice "创建成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 409 {object} map[string]string "与其他定时价格的时间窗口重叠"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/scheduled-prices [post]
+func (c *PriceHistoryController) SchedulePrice(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req models.SchedulePriceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scheduled, err := c.priceHistoryService.ForTenant(ctx.GetUint("orgID")).SchedulePrice(uint(id), &req, middleware.CurrentActor(ctx))
+	if err != nil {
+		scheduledPriceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, scheduled)
+}
+
+// CancelScheduledPrice godoc
+// @Summary 取消定时价格
+// @Description 取消等待生效或生效中的定时价格，生效中的定时价格取消时立即恢复生效前的价格
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param scheduledId path int true "定时价格ID"
+// @Success 200 {object} models.ScheduledPrice "取消成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品或定时价格不存在"
+// @Failure 409 {object} map[string]string "定时价格已结束或已取消"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/scheduled-prices/{scheduledId}/cancel [post]
+func (c *PriceHistoryController) CancelScheduledPrice(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	scheduledID, err := strconv.ParseUint(ctx.Param("scheduledId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scheduled price ID"})
+		return
+	}
+
+	scheduled, err := c.priceHistoryService.ForTenant(ctx.GetUint("orgID")).CancelScheduled(uint(id), uint(scheduledID), middleware.CurrentActor(ctx))
+	if err != nil {
+		scheduledPriceErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+func scheduledPriceErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrScheduledPriceNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidPriceWindow), errors.Is(err, services.ErrPriceManagedByVariants):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPriceWindowOverlap), errors.Is(err, services.ErrScheduledPriceClosed):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		productErrorResponse(ctx, err)
+	}
+}
@@ -1,15 +1,23 @@
 package controllers
 
 import (
+	"errors"
+	"go-webapi-example/listing"
+	"go-webapi-example/middleware"
 	"go-webapi-example/models"
 	"go-webapi-example/services"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+const maxSearchQueryLength = 200
+
 type ProductController struct {
 	productService *services.ProductService
 }
@@ -22,15 +30,17 @@ func NewProductController(db *gorm.DB) *ProductController {
 
 // CreateProduct godoc
 // @Summary 创建新产品
-// @Description 创建一个新的产品记录，需要提供产品基本信息
+// @Description 创建一个新的产品记录，创建者为当前登录用户。可以同时提交变体，此时产品的价格区间和库存由变体汇总
 // @Tags products
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Param product body models.CreateProductRequest true "产品创建信息"
 // @Success 201 {object} models.Product "创建成功，返回产品详细信息"
 // @Failure 400 {object} map[string]string "请求参数错误"
 // @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 409 {object} map[string]string "SKU 已被其他变体使用"
 // @Failure 500 {object} map[string]string "服务器内部错误"
 // @Router /products [post]
 func (c *ProductController) CreateProduct(ctx *gin.Context) {
@@ -40,9 +50,15 @@ func (c *ProductController) CreateProduct(ctx *gin.Context) {
 		return
 	}
 
-	product, err := c.productService.CreateProduct(&req)
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	product, err := c.productService.ForTenant(ctx.GetUint("orgID")).CreateProduct(&req, userID.(uint))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		productErrorResponse(ctx, err)
 		return
 	}
 
@@ -55,6 +71,7 @@ func (c *ProductController) CreateProduct(ctx *gin.Context) {
 // @Tags products
 // @Produce json
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Param id path int true "产品ID" minimum(1) example(1)
 // @Success 200 {object} models.Product "获取成功，返回产品详细信息"
 // @Failure 400 {object} map[string]string "请求参数错误"
@@ -68,7 +85,7 @@ func (c *ProductController) GetProduct(ctx *gin.Context) {
 		return
 	}
 
-	product, err := c.productService.GetProductByID(uint(id))
+	product, err := c.productService.ForTenant(ctx.GetUint("orgID")).GetProductByID(uint(id))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
@@ -83,37 +100,120 @@ func (c *ProductController) GetProduct(ctx *gin.Context) {
 
 // GetProducts godoc
 // @Summary 获取产品列表
-// @Description 获取所有产品的列表，包括产品基本信息和关联用户信息
+// @Description 分页获取产品列表，包括产品基本信息和关联用户信息。分页使用 page/page_size、limit/offset 或游标 cursor/limit（每页最多 100 条），
+// @Description sort 为逗号分隔的排序字段（id、name、price、stock、created_at、updated_at），字段前加 - 表示倒序，默认 -created_at
 // @Tags products
 // @Produce json
 // @Security ApiKeyAuth
-// @Success 200 {array} models.Product "获取成功，返回产品列表"
+// @Security APIKeyHeader
+// @Param page query int false "页码，从 1 开始"
+// @Param page_size query int false "每页数量，默认 20"
+// @Param limit query int false "每页数量（与 offset 配合使用）"
+// @Param offset query int false "偏移量"
+// @Param cursor query string false "游标分页：首页传空值，之后传 next_cursor 或 prev_cursor"
+// @Param sort query string false "排序字段，例如 -price,name；按 price 排序需要同时指定 currency"
+// @Param min_price query number false "最低价格（主单位，例如 9.99），需要同时指定 currency"
+// @Param max_price query number false "最高价格，需要同时指定 currency"
+// @Param currency query string false "只返回以该货币计价的产品，例如 USD"
+// @Param in_stock query bool false "是否有库存"
+// @Param owner_id query int false "创建用户ID"
+// @Param category_id query int false "分类ID（包含子孙分类中的产品）"
+// @Param tags query string false "逗号分隔的标签，匹配任意一个"
+// @Param tags_all query string false "逗号分隔的标签，需要全部匹配"
+// @Param created_from query string false "创建时间起（RFC3339 或 YYYY-MM-DD）"
+// @Param created_to query string false "创建时间止（RFC3339 或 YYYY-MM-DD）"
+// @Success 200 {object} models.ProductListResponse "获取成功，返回当前页产品和分页信息"
+// @Failure 400 {object} map[string]string "查询参数错误"
 // @Failure 401 {object} map[string]string "未授权访问"
 // @Failure 500 {object} map[string]string "服务器内部错误"
 // @Router /products [get]
 func (c *ProductController) GetProducts(ctx *gin.Context) {
-	products, err := c.productService.GetAllProducts()
+	params, err := listing.Parse(ctx.Request.URL.Query(), services.ProductListSpec)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := c.productService.ForTenant(ctx.GetUint("orgID")).ListProducts(params)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.ProductListResponse{
+		Data:       page.Items,
+		Pagination: newPagination(ctx, params, page),
+	})
+}
+
+// SearchProducts godoc
+// @Summary 搜索产品
+// @Description 按名称和描述搜索产品，结果按相关度排序并返回高亮片段。优先使用全文检索，
+// @Description 没有结果时（拼写错误、中文等）退回三元组相似度和子串匹配。支持产品列表的过滤参数和 page/page_size、limit/offset 分页
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param q query string true "搜索关键词，支持 websearch 语法（\"短语\"、or、-排除）"
+// @Param page query int false "页码，从 1 开始"
+// @Param page_size query int false "每页数量，默认 20"
+// @Param limit query int false "每页数量（与 offset 配合使用）"
+// @Param offset query int false "偏移量"
+// @Param min_price query number false "最低价格（主单位，例如 9.99），需要同时指定 currency"
+// @Param max_price query number false "最高价格，需要同时指定 currency"
+// @Param currency query string false "只返回以该货币计价的产品，例如 USD"
+// @Param in_stock query bool false "是否有库存"
+// @Param owner_id query int false "创建用户ID"
+// @Success 200 {object} models.ProductSearchResponse "搜索成功"
+// @Failure 400 {object} map[string]string "查询参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/search [get]
+func (c *ProductController) SearchProducts(ctx *gin.Context) {
+	q := strings.TrimSpace(ctx.Query("q"))
+	if q == "" || utf8.RuneCountInString(q) > maxSearchQueryLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "q is required and must be at most 200 characters"})
+		return
+	}
+
+	params, err := listing.Parse(ctx.Request.URL.Query(), services.ProductSearchSpec)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if params.UsesCursor() {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "cursor pagination is not supported for search"})
+		return
+	}
+
+	page, err := c.productService.ForTenant(ctx.GetUint("orgID")).SearchProducts(q, params)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, products)
+	ctx.JSON(http.StatusOK, models.ProductSearchResponse{
+		Data:       page.Items,
+		Pagination: newPagination(ctx, params, page),
+	})
 }
 
 // UpdateProduct godoc
 // @Summary 更新产品信息
-// @Description 根据产品ID更新产品的详细信息，支持部分字段更新
+// @Description 根据产品ID更新产品的详细信息，支持部分字段更新。提交 variants 时整体替换产品变体。只有创建者或拥有 products:manage 权限的管理员可以修改
 // @Tags products
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Param id path int true "产品ID" minimum(1) example(1)
 // @Param product body models.UpdateProductRequest true "产品更新信息"
 // @Success 200 {object} models.Product "更新成功，返回更新后的产品信息"
 // @Failure 400 {object} map[string]string "请求参数错误"
 // @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
 // @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 409 {object} map[string]string "SKU 已被其他变体使用"
 // @Failure 500 {object} map[string]string "服务器内部错误"
 // @Router /products/{id} [put]
 func (c *ProductController) UpdateProduct(ctx *gin.Context) {
@@ -129,28 +229,194 @@ func (c *ProductController) UpdateProduct(ctx *gin.Context) {
 		return
 	}
 
-	product, err := c.productService.UpdateProduct(uint(id), &req)
+	product, err := c.productService.ForTenant(ctx.GetUint("orgID")).UpdateProduct(uint(id), &req, middleware.CurrentActor(ctx))
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		productErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, product)
+}
+
+// GetProductFacets godoc
+// @Summary 产品分面统计
+// @Description 按与产品列表相同的过滤条件统计产品总数、各标签的产品数（最多 50 个）和各价格区间的产品数，用于渲染筛选侧栏。
+// @Description 不同货币的价格不能放在同一组区间里，只有指定 currency 时才返回 price_buckets
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param price_buckets query string false "逗号分隔的升序价格区间边界，默认 0,50,100,200,500,1000,5000，需要同时指定 currency"
+// @Param min_price query number false "最低价格（主单位，例如 9.99），需要同时指定 currency"
+// @Param max_price query number false "最高价格，需要同时指定 currency"
+// @Param currency query string false "只返回以该货币计价的产品，例如 USD"
+// @Param in_stock query bool false "是否有库存"
+// @Param owner_id query int false "创建用户ID"
+// @Param category_id query int false "分类ID（包含子孙分类中的产品）"
+// @Param tags query string false "逗号分隔的标签，匹配任意一个"
+// @Param tags_all query string false "逗号分隔的标签，需要全部匹配"
+// @Param created_from query string false "创建时间起（RFC3339 或 YYYY-MM-DD）"
+// @Param created_to query string false "创建时间止（RFC3339 或 YYYY-MM-DD）"
+// @Success 200 {object} models.ProductFacetsResponse "统计成功"
+// @Failure 400 {object} map[string]string "查询参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/facets [get]
+func (c *ProductController) GetProductFacets(ctx *gin.Context) {
+	params, err := listing.Parse(ctx.Request.URL.Query(), services.ProductListSpec)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 价格区间只统计同一货币的产品
+	var bounds []float64
+	raw := ctx.Query("price_buckets")
+	switch {
+	case ctx.Query("currency") == "" && raw != "":
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "price_buckets requires currency"})
+		return
+	case ctx.Query("currency") == "":
+	case raw != "":
+		if bounds, err = parsePriceBuckets(raw); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+	default:
+		bounds = services.DefaultPriceBuckets
+	}
+
+	facets, err := c.productService.ForTenant(ctx.GetUint("orgID")).GetFacets(params, bounds)
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	ctx.JSON(http.StatusOK, facets)
+}
+
+// AddProductTags godoc
+// @Summary 添加产品标签
+// @Description 为产品添加一个或多个标签，标签名称不区分大小写，不存在的标签自动创建。只有创建者或拥有 products:manage 权限的管理员可以修改
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param tags body models.AddProductTagsRequest true "标签名称列表"
+// @Success 200 {object} models.Product "添加成功，返回产品及全部标签"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/tags [post]
+func (c *ProductController) AddProductTags(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req models.AddProductTagsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := c.productService.ForTenant(ctx.GetUint("orgID")).AddProductTags(uint(id), req.Tags, middleware.CurrentActor(ctx))
+	if err != nil {
+		productErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, product)
+}
+
+// RemoveProductTag godoc
+// @Summary 移除产品标签
+// @Description 移除产品的一个标签，产品没有该标签时直接返回产品。只有创建者或拥有 products:manage 权限的管理员可以修改
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param tag path string true "标签名称"
+// @Success 200 {object} models.Product "移除成功，返回产品及剩余标签"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/tags/{tag} [delete]
+func (c *ProductController) RemoveProductTag(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	product, err := c.productService.ForTenant(ctx.GetUint("orgID")).RemoveProductTag(uint(id), ctx.Param("tag"), middleware.CurrentActor(ctx))
+	if err != nil {
+		productErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, product)
+}
+
+// SetProductCategories godoc
+// @Summary 设置产品分类
+// @Description 整体替换产品所属的分类，空列表表示清除全部分类。只有创建者或拥有 products:manage 权限的管理员可以修改
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param categories body models.SetProductCategoriesRequest true "分类ID列表"
+// @Success 200 {object} models.Product "设置成功，返回产品及分类面包屑"
+// @Failure 400 {object} map[string]string "请求参数错误或分类不存在"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权修改该产品"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/categories [put]
+func (c *ProductController) SetProductCategories(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req models.SetProductCategoriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := c.productService.ForTenant(ctx.GetUint("orgID")).SetProductCategories(uint(id), req.CategoryIDs, middleware.CurrentActor(ctx))
+	if err != nil {
+		productErrorResponse(ctx, err)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, product)
 }
 
 // DeleteProduct godoc
 // @Summary 删除产品
-// @Description 根据产品ID删除指定产品（软删除）
+// @Description 根据产品ID删除指定产品（软删除）。只有创建者或拥有 products:manage 权限的管理员可以删除
 // @Tags products
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Param id path int true "产品ID" minimum(1) example(1)
 // @Success 204 "删除成功，无返回内容"
 // @Failure 400 {object} map[string]string "请求参数错误"
 // @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权删除该产品"
+// @Failure 404 {object} map[string]string "产品不存在"
 // @Failure 500 {object} map[string]string "服务器内部错误"
 // @Router /products/{id} [delete]
 func (c *ProductController) DeleteProduct(ctx *gin.Context) {
@@ -160,10 +426,57 @@ func (c *ProductController) DeleteProduct(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.productService.DeleteProduct(uint(id)); err != nil {
+	if err := c.productService.ForTenant(ctx.GetUint("orgID")).DeleteProduct(uint(id), middleware.CurrentActor(ctx)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		if errors.Is(err, services.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// productErrorResponse 产品修改类接口的通用错误响应
+func productErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+	case errors.Is(err, services.ErrForbidden):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnknownCategory), errors.Is(err, services.ErrUnknownVariant), errors.Is(err, services.ErrDuplicateVariant),
+		errors.Is(err, services.ErrVariantRequired), errors.Is(err, services.ErrStockManagedByVariants), errors.Is(err, services.ErrInvalidMovementType),
+		errors.Is(err, services.ErrNegativePrice), errors.Is(err, services.ErrCurrencyMismatch), errors.Is(err, services.ErrVariantPrice):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSKUExists), errors.Is(err, services.ErrInsufficientStock),
+		errors.Is(err, services.ErrVariantReserved), errors.Is(err, services.ErrProductReserved):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// parsePriceBuckets 解析逗号分隔的价格区间边界，要求为严格升序的非负数，最多 20 个
+func parsePriceBuckets(raw string) ([]float64, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) > 20 {
+		return nil, errors.New("price_buckets accepts at most 20 boundaries")
+	}
+	bounds := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		b, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(b) || math.IsInf(b, 0) || b < 0 {
+			return nil, errors.New("price_buckets must be non-negative numbers")
+		}
+		if len(bounds) > 0 && b <= bounds[len(bounds)-1] {
+			return nil, errors.New("price_buckets must be in ascending order")
+		}
+		bounds = append(bounds, b)
+	}
+	return bounds, nil
+}
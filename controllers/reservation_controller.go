@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"errors"
+	"go-webapi-example/config"
+	"go-webapi-example/middleware"
+	"go-webapi-example/models"
+	"go-webapi-example/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ReservationController struct {
+	reservationService *services.ReservationService
+}
+
+func NewReservationController(db *gorm.DB, cfg *config.Config) *ReservationController {
+	return &ReservationController{
+		reservationService: services.NewReservationService(db, cfg),
+	}
+}
+
+// ReserveStock godoc
+// @Summary 预留库存
+// @Description 结账时为当前用户预留产品库存，预留中的数量从可售库存（available）中扣除，超时未确认自动释放。
+// @Description 有变体的产品必须指定 variant_id
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "产品ID"
+// @Param reservation body models.ReserveStockRequest true "预留数量和时长"
+// @Success 201 {object} models.StockReservation "预留成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "产品不存在"
+// @Failure 409 {object} map[string]string "可售库存不足或有效预留过多"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /products/{id}/reservations [post]
+func (c *ReservationController) ReserveStock(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req models.ReserveStockRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reservation, err := c.reservationService.ForTenant(ctx.GetUint("orgID")).Reserve(uint(id), &req, middleware.CurrentActor(ctx))
+	if err != nil {
+		reservationErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, reservation)
+}
+
+// GetReservation godoc
+// @Summary 获取库存预留
+// @Description 只有预留用户或拥有 products:manage 权限的管理员可以查看
+// @Tags inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "预留ID"
+// @Success 200 {object} models.StockReservation "获取成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权查看该预留"
+// @Failure 404 {object} map[string]string "预留不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /reservations/{id} [get]
+func (c *ReservationController) GetReservation(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation ID"})
+		return
+	}
+
+	reservation, err := c.reservationService.ForTenant(ctx.GetUint("orgID")).GetReservation(uint(id), middleware.CurrentActor(ctx))
+	if err != nil {
+		reservationErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reservation)
+}
+
+// ConfirmReservation godoc
+// @Summary 确认库存预留
+// @Description 确认预留并扣减实际库存（记为一次 sell 库存流水）。已过期、已确认或已释放的预留不能确认
+// @Tags inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "预留ID"
+// @Success 200 {object} models.StockReservation "确认成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权操作该预留"
+// @Failure 404 {object} map[string]string "预留不存在"
+// @Failure 409 {object} map[string]string "预留已结束或已过期"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /reservations/{id}/confirm [post]
+func (c *ReservationController) ConfirmReservation(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation ID"})
+		return
+	}
+
+	reservation, err := c.reservationService.ForTenant(ctx.GetUint("orgID")).Confirm(uint(id), middleware.CurrentActor(ctx))
+	if err != nil {
+		reservationErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reservation)
+}
+
+// ReleaseReservation godoc
+// @Summary 释放库存预留
+// @Description 取消预留，占用的可售库存立即恢复
+// @Tags inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "预留ID"
+// @Success 200 {object} models.StockReservation "释放成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "无权操作该预留"
+// @Failure 404 {object} map[string]string "预留不存在"
+// @Failure 409 {object} map[string]string "预留已结束或已过期"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /reservations/{id}/release [post]
+func (c *ReservationController) ReleaseReservation(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation ID"})
+		return
+	}
+
+	reservation, err := c.reservationService.ForTenant(ctx.GetUint("orgID")).Release(uint(id), middleware.CurrentActor(ctx))
+	if err != nil {
+		reservationErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reservation)
+}
+
+func reservationErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+	case errors.Is(err, services.ErrReservationNotActive), errors.Is(err, services.ErrReservationExpired),
+		errors.Is(err, services.ErrTooManyReservations):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		productErrorResponse(ctx, err)
+	}
+}
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"errors"
+	"go-webapi-example/models"
+	"go-webapi-example/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RoleController struct {
+	rbacService *services.RBACService
+}
+
+func NewRoleController(db *gorm.DB) *RoleController {
+	return &RoleController{
+		rbacService: services.NewRBACService(db),
+	}
+}
+
+// GetPermissions godoc
+// @Summary 获取全部权限（超级管理员）
+// @Description 返回系统支持的全部权限标识及说明
+// @Tags roles
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {array} models.Permission "获取成功"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/permissions [get]
+func (c *RoleController) GetPermissions(ctx *gin.Context) {
+	permissions, err := c.rbacService.GetPermissions()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, permissions)
+}
+
+// GetRoles godoc
+// @Summary 获取全部角色（超级管理员）
+// @Description 返回全部角色及其权限
+// @Tags roles
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {array} models.Role "获取成功"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/roles [get]
+func (c *RoleController) GetRoles(ctx *gin.Context) {
+	roles, err := c.rbacService.GetRoles()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roles)
+}
+
+// CreateRole godoc
+// @Summary 创建角色（超级管理员）
+// @Description 创建自定义角色并指定权限
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param role body models.CreateRoleRequest true "角色信息"
+// @Success 201 {object} models.Role "创建成功"
+// @Failure 400 {object} map[string]string "请求参数错误或权限不存在"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 409 {object} map[string]string "角色已存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/roles [post]
+func (c *RoleController) CreateRole(ctx *gin.Context) {
+	var req models.CreateRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role, err := c.rbacService.CreateRole(&req)
+	if err != nil {
+		ctx.JSON(roleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, role)
+}
+
+// UpdateRole godoc
+// @Summary 更新角色（超级管理员）
+// @Description 更新角色说明并整体替换权限列表，权限有变化时该角色全部用户已签发的访问令牌立即失效，刷新令牌后按新权限签发。superadmin 角色的权限不可修改
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "角色ID"
+// @Param role body models.UpdateRoleRequest true "角色信息"
+// @Success 200 {object} models.Role "更新成功"
+// @Failure 400 {object} map[string]string "请求参数错误或权限不存在"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足或角色不可修改"
+// @Failure 404 {object} map[string]string "角色不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/roles/{id} [put]
+func (c *RoleController) UpdateRole(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		return
+	}
+
+	var req models.UpdateRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role, err := c.rbacService.UpdateRole(uint(id), &req)
+	if err != nil {
+		ctx.JSON(roleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, role)
+}
+
+// DeleteRole godoc
+// @Summary 删除角色（超级管理员）
+// @Description 删除自定义角色，内置角色和仍被用户使用的角色不能删除
+// @Tags roles
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "角色ID"
+// @Success 204 "删除成功，无返回内容"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足或内置角色不可删除"
+// @Failure 404 {object} map[string]string "角色不存在"
+// @Failure 409 {object} map[string]string "角色仍被用户使用"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/roles/{id} [delete]
+func (c *RoleController) DeleteRole(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		return
+	}
+
+	if err := c.rbacService.DeleteRole(uint(id)); err != nil {
+		ctx.JSON(roleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func roleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrUnknownPermission), errors.Is(err, services.ErrUnknownRole):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrSystemRoleImmutable):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrRoleExists), errors.Is(err, services.ErrRoleInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"errors"
+	"go-webapi-example/config"
+	"go-webapi-example/models"
+	"go-webapi-example/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TwoFactorController struct {
+	twoFactorService *services.TwoFactorService
+}
+
+func NewTwoFactorController(db *gorm.DB, cfg *config.Config) *TwoFactorController {
+	return &TwoFactorController{
+		twoFactorService: services.NewTwoFactorService(db, cfg),
+	}
+}
+
+// Setup godoc
+// @Summary 开始注册二步验证
+// @Description 为当前用户生成待确认的 TOTP 密钥，返回密钥和 otpauth:// 二维码地址，需调用启用接口提交验证码后生效
+// @Tags two-factor
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.TwoFactorSetupResponse "生成成功"
+// @Failure 400 {object} map[string]string "二步验证已启用"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /users/profile/2fa/setup [post]
+func (c *TwoFactorController) Setup(ctx *gin.Context) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	setup, err := c.twoFactorService.Setup(userID.(uint))
+	if err != nil {
+		ctx.JSON(twoFactorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, setup)
+}
+
+// Enable godoc
+// @Summary 启用二步验证
+// @Description 提交验证器应用中的验证码确认密钥，启用成功后返回一次性恢复码（只展示一次）
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.TwoFactorCodeRequest true "TOTP 验证码"
+// @Success 200 {object} models.RecoveryCodesResponse "启用成功，返回恢复码"
+// @Failure 400 {object} map[string]string "请求参数错误、验证码错误或尚未开始注册"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /users/profile/2fa/enable [post]
+func (c *TwoFactorController) Enable(ctx *gin.Context) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := c.twoFactorService.Enable(userID.(uint), req.Code)
+	if err != nil {
+		ctx.JSON(twoFactorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable godoc
+// @Summary 关闭二步验证
+// @Description 验证当前密码和验证码（或恢复码）后关闭二步验证；角色要求二步验证的用户不能关闭
+// @Tags two-factor
+// @Accept json
+// @Security ApiKeyAuth
+// @Param request body models.DisableTwoFactorRequest true "当前密码和验证码"
+// @Success 204 "关闭成功，无返回内容"
+// @Failure 400 {object} map[string]string "请求参数错误、密码或验证码错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "当前角色必须启用二步验证"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /users/profile/2fa/disable [post]
+func (c *TwoFactorController) Disable(ctx *gin.Context) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.DisableTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.twoFactorService.Disable(userID.(uint), &req); err != nil {
+		ctx.JSON(twoFactorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary 重新生成恢复码
+// @Description 验证 TOTP 验证码后重新生成一次性恢复码，旧恢复码全部失效
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.TwoFactorCodeRequest true "TOTP 验证码"
+// @Success 200 {object} models.RecoveryCodesResponse "生成成功，返回新的恢复码"
+// @Failure 400 {object} map[string]string "请求参数错误、验证码错误或二步验证未启用"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /users/profile/2fa/recovery-codes [post]
+func (c *TwoFactorController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := c.twoFactorService.RegenerateRecoveryCodes(userID.(uint), req.Code)
+	if err != nil {
+		ctx.JSON(twoFactorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// twoFactorErrorStatus 将二步验证相关错误映射为 HTTP 状态码
+func twoFactorErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, services.ErrTwoFactorNotEnabled),
+		errors.Is(err, services.ErrTwoFactorSetupRequired),
+		errors.Is(err, services.ErrInvalidTwoFactorCode),
+		errors.Is(err, services.ErrIncorrectPassword):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrTwoFactorRequiredByRole):
+		return http.StatusForbidden
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
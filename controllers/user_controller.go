@@ -1,6 +1,10 @@
 package controllers
 
 import (
+	"errors"
+	"go-webapi-example/config"
+	"go-webapi-example/listing"
+	"go-webapi-example/middleware"
 	"go-webapi-example/models"
 	"go-webapi-example/services"
 	"net/http"
@@ -11,22 +15,25 @@ import (
 )
 
 type UserController struct {
-	userService *services.UserService
+	userService       *services.UserService
+	loginGuardService *services.LoginGuardService
 }
 
-func NewUserController(db *gorm.DB) *UserController {
+func NewUserController(db *gorm.DB, cfg *config.Config) *UserController {
 	return &UserController{
-		userService: services.NewUserService(db),
+		userService:       services.NewUserService(db, cfg),
+		loginGuardService: services.NewLoginGuardService(db, cfg),
 	}
 }
 
 // CreateUser godoc
 // @Summary 创建新用户（管理员）
-// @Description 管理员创建新用户账户，可以指定用户角色
+// @Description 管理员创建新用户账户；指定 user 以外的角色需要 roles:manage 权限
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Param user body models.CreateUserRequest true "用户创建信息"
 // @Success 201 {object} models.User "创建成功，返回用户详细信息"
 // @Failure 400 {object} map[string]string "请求参数错误或邮箱已存在"
@@ -41,8 +48,18 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 		return
 	}
 
-	user, err := c.userService.CreateUser(&req)
+	// 只有能管理角色的用户才能直接创建高权限账户，superadmin 账户只能由超级管理员创建
+	if req.Role != "" && !middleware.CurrentActor(ctx).CanAssignRole(req.Role) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to assign role " + req.Role})
+		return
+	}
+
+	user, err := c.userService.ForTenant(ctx.GetUint("orgID")).CreateUser(&req)
 	if err != nil {
+		if errors.Is(err, services.ErrUnknownRole) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -67,7 +84,7 @@ func (c *UserController) GetUser(ctx *gin.Context) {
 		return
 	}
 
-	user, err := c.userService.GetUserByID(uint(id))
+	user, err := c.userService.ForTenant(ctx.GetUint("orgID")).GetUserByID(uint(id))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
@@ -82,25 +99,46 @@ func (c *UserController) GetUser(ctx *gin.Context) {
 
 // GetUsers godoc
 // @Summary 获取所有用户
-// @Description 获取用户列表
+// @Description 分页获取当前组织的用户列表。分页和排序参数与产品列表相同，可排序字段为 id、name、email、age、created_at，默认 id
 // @Tags users
 // @Produce json
-// @Success 200 {array} models.User
+// @Param page query int false "页码，从 1 开始"
+// @Param page_size query int false "每页数量，默认 20"
+// @Param limit query int false "每页数量（与 offset 配合使用）"
+// @Param offset query int false "偏移量"
+// @Param cursor query string false "游标分页：首页传空值，之后传 next_cursor 或 prev_cursor"
+// @Param sort query string false "排序字段，例如 -created_at"
+// @Param role query string false "角色"
+// @Param is_active query bool false "是否激活"
+// @Param email_verified query bool false "邮箱是否已验证"
+// @Param created_from query string false "创建时间起（RFC3339 或 YYYY-MM-DD）"
+// @Param created_to query string false "创建时间止（RFC3339 或 YYYY-MM-DD）"
+// @Success 200 {object} models.UserListResponse
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /users [get]
 func (c *UserController) GetUsers(ctx *gin.Context) {
-	users, err := c.userService.GetAllUsers()
+	params, err := listing.Parse(ctx.Request.URL.Query(), services.UserListSpec)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := c.userService.ForTenant(ctx.GetUint("orgID")).ListUsers(params)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, users)
+	ctx.JSON(http.StatusOK, models.UserListResponse{
+		Data:       page.Items,
+		Pagination: newPagination(ctx, params, page),
+	})
 }
 
 // UpdateUser godoc
 // @Summary 更新用户
-// @Description 根据ID更新用户信息
+// @Description 根据ID更新用户信息。用户只能修改自己，拥有 users:write 权限的管理员可以修改本组织的其他用户；超级管理员和同时属于其他组织的用户只能由超级管理员修改
 // @Tags users
 // @Accept json
 // @Produce json
@@ -108,6 +146,7 @@ func (c *UserController) GetUsers(ctx *gin.Context) {
 // @Param user body models.UpdateUserRequest true "用户信息"
 // @Success 200 {object} models.User
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /users/{id} [put]
@@ -124,13 +163,13 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
-	user, err := c.userService.UpdateUser(uint(id), &req)
+	user, err := c.userService.ForTenant(ctx.GetUint("orgID")).UpdateUser(uint(id), &req, middleware.CurrentActor(ctx))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(userAdminErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -139,11 +178,14 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 
 // DeleteUser godoc
 // @Summary 删除用户
-// @Description 根据ID删除用户
+// @Description 根据ID删除用户。超级管理员和同时属于其他组织的用户只能由超级管理员删除，最后一个超级管理员不能删除
 // @Tags users
 // @Param id path int true "用户ID"
 // @Success 204
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /users/{id} [delete]
 func (c *UserController) DeleteUser(ctx *gin.Context) {
@@ -153,8 +195,12 @@ func (c *UserController) DeleteUser(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.userService.DeleteUser(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.userService.ForTenant(ctx.GetUint("orgID")).DeleteUser(uint(id), middleware.CurrentActor(ctx)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(userAdminErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -167,6 +213,7 @@ func (c *UserController) DeleteUser(ctx *gin.Context) {
 // @Tags users
 // @Produce json
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 200 {object} models.UserProfile "获取成功，返回用户资料"
 // @Failure 401 {object} map[string]string "未授权访问或令牌无效"
 // @Router /users/profile [get]
@@ -177,20 +224,361 @@ func (c *UserController) GetProfile(ctx *gin.Context) {
 		return
 	}
 
-	user, err := c.userService.GetUserByID(userID.(uint))
+	user, err := c.userService.GetAccount(userID.(uint))
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
 
-	profile := models.UserProfile{
-		ID:       user.ID,
-		Name:     user.Name,
-		Email:    user.Email,
-		Age:      user.Age,
-		Role:     user.Role,
-		IsActive: user.IsActive,
+	ctx.JSON(http.StatusOK, user.Profile())
+}
+
+// ChangePassword godoc
+// @Summary 修改当前用户密码
+// @Description 验证当前密码后设置新密码，修改成功后该用户的全部令牌失效，需要重新登录
+// @Tags users
+// @Accept json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param password body models.ChangePasswordRequest true "当前密码和新密码"
+// @Success 204 "修改成功，无返回内容"
+// @Failure 400 {object} map[string]string "请求参数错误或当前密码错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /users/profile/password [put]
+func (c *UserController) ChangePassword(ctx *gin.Context) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.ChangePassword(userID.(uint), &req); err != nil {
+		if errors.Is(err, services.ErrIncorrectPassword) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ResendVerification godoc
+// @Summary 重新发送邮箱验证邮件（管理员）
+// @Description 管理员为指定用户重新发送邮箱验证链接
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "用户ID"
+// @Success 200 {object} models.SuccessResponse "发送成功"
+// @Failure 400 {object} map[string]string "请求参数错误或邮箱已验证"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "用户不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/users/{id}/verification/resend [post]
+func (c *UserController) ResendVerification(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	user, err := c.userService.ForTenant(ctx.GetUint("orgID")).GetUserByID(uint(id))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.ForTenant(ctx.GetUint("orgID")).SendVerificationEmail(user); err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyVerified) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.SuccessResponse{Message: "Verification email sent"})
+}
+
+// ForceVerify godoc
+// @Summary 强制验证用户邮箱（管理员）
+// @Description 管理员直接将指定用户的邮箱标记为已验证
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "用户ID"
+// @Success 200 {object} models.User "验证成功，返回用户信息"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "用户不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/users/{id}/verify [post]
+func (c *UserController) ForceVerify(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	user, err := c.userService.ForTenant(ctx.GetUint("orgID")).ForceVerifyEmail(uint(id))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// UnlockUser godoc
+// @Summary 解锁用户账户（管理员）
+// @Description 清除指定用户的登录失败计数和锁定状态，并记录解锁事件
+// @Tags admin
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "用户ID"
+// @Success 204 "解锁成功，无返回内容"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 404 {object} map[string]string "用户不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/users/{id}/unlock [post]
+func (c *UserController) UnlockUser(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// 只能解锁当前组织的成员
+	if _, err := c.userService.ForTenant(ctx.GetUint("orgID")).GetUserByID(uint(id)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	actorID, _ := ctx.Get("userID")
+	if err := c.loginGuardService.Unlock(uint(id), actorID.(uint)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetLockoutEvents godoc
+// @Summary 获取账户锁定事件（管理员）
+// @Description 按时间倒序返回账户锁定、IP 封禁和解锁事件；没有 organizations:manage 权限时只返回当前组织成员的账户事件
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param user_id query int false "只返回指定用户的事件"
+// @Param limit query int false "返回条数（默认50，最大500）"
+// @Success 200 {array} models.LockoutEvent "获取成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/lockout-events [get]
+func (c *UserController) GetLockoutEvents(ctx *gin.Context) {
+	var userID uint64
+	if v := ctx.Query("user_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		userID = id
+	}
+
+	limit := 50
+	if v := ctx.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		limit = min(n, 500)
+	}
+
+	// 跨组织的管理员可以看到全部事件，其他管理员只能看到本组织成员的事件
+	var orgID uint
+	if !middleware.HasPermission(ctx, models.PermOrgsManage) {
+		orgID = ctx.GetUint("orgID")
+	}
+
+	events, err := c.loginGuardService.ListLockoutEvents(uint(userID), orgID, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
+
+// AssignRole godoc
+// @Summary 为用户分配角色（超级管理员）
+// @Description 提升或降级指定用户的角色，该用户已签发的全部令牌立即失效。不能修改自己的角色，只有超级管理员可以授予或收回 superadmin 角色，最后一个超级管理员不能降级；同时属于其他组织的用户只能由超级管理员修改角色
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "用户ID"
+// @Param role body models.AssignRoleRequest true "角色名称"
+// @Success 200 {object} models.User "分配成功，返回用户信息"
+// @Failure 400 {object} map[string]string "请求参数错误或角色不存在"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足或不能修改自己的角色"
+// @Failure 404 {object} map[string]string "用户不存在"
+// @Failure 409 {object} map[string]string "不能降级最后一个超级管理员"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/users/{id}/role [put]
+func (c *UserController) AssignRole(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.AssignRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.userService.ForTenant(ctx.GetUint("orgID")).UpdateUserRole(uint(id), req.Role, middleware.CurrentActor(ctx))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(userAdminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// UpdateUserStatus godoc
+// @Summary 激活或停用用户（超级管理员）
+// @Description 激活或停用指定用户，停用后该用户已签发的令牌和 API 密钥立即失效。不能停用自己，最后一个超级管理员不能停用；同时属于其他组织的用户只能由超级管理员激活或停用
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "用户ID"
+// @Param status body models.UpdateUserStatusRequest true "账户状态"
+// @Success 200 {object} models.User "修改成功，返回用户信息"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "权限不足或不能停用自己"
+// @Failure 404 {object} map[string]string "用户不存在"
+// @Failure 409 {object} map[string]string "不能停用最后一个超级管理员"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/users/{id}/status [put]
+func (c *UserController) UpdateUserStatus(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.UpdateUserStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.userService.ForTenant(ctx.GetUint("orgID")).SetUserActive(uint(id), *req.IsActive, middleware.CurrentActor(ctx))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(userAdminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// TransferSuperAdmin godoc
+// @Summary 移交超级管理员（超级管理员）
+// @Description 将当前用户的 superadmin 角色移交给另一个有效用户，当前用户降级为 admin，双方已签发的令牌全部失效
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param request body models.TransferSuperAdminRequest true "接收用户"
+// @Success 200 {object} models.User "移交成功，返回新的超级管理员"
+// @Failure 400 {object} map[string]string "请求参数错误或接收用户无效"
+// @Failure 401 {object} map[string]string "未授权访问"
+// @Failure 403 {object} map[string]string "当前用户不是超级管理员"
+// @Failure 404 {object} map[string]string "用户不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /admin/superadmin/transfer [post]
+func (c *UserController) TransferSuperAdmin(ctx *gin.Context) {
+	var req models.TransferSuperAdminRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.userService.TransferSuperAdmin(middleware.CurrentActor(ctx), req.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		ctx.JSON(userAdminErrorStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
-	ctx.JSON(http.StatusOK, profile)
+	ctx.JSON(http.StatusOK, user)
+}
+
+func userAdminErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrUnknownRole), errors.Is(err, services.ErrInvalidTransferTarget):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrCannotModifySelf), errors.Is(err, services.ErrSuperAdminRequired),
+		errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrSharedAccount):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrLastSuperAdmin):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
 }
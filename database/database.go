@@ -1,9 +1,13 @@
 package database
 
 import (
+	"fmt"
 	"go-webapi-example/config"
 	"go-webapi-example/models"
+	"go-webapi-example/money"
 	"go-webapi-example/services"
+	"go-webapi-example/tenant"
+	"math"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -18,20 +22,148 @@ func Initialize(cfg *config.Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// 注册租户隔离回调
+	if err := tenant.Register(db); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
 
-func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+func Migrate(db *gorm.DB, cfg *config.Config) error {
+	// 迁移需要跨组织访问全部数据
+	db = tenant.System(db)
+
+	if err := db.AutoMigrate(
 		&models.User{},
 		&models.Product{},
-	)
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.PasswordResetToken{},
+		&models.RecoveryCode{},
+		&models.LoginAttempt{},
+		&models.LockoutEvent{},
+		&models.APIKey{},
+		&models.Permission{},
+		&models.Role{},
+		&models.Organization{},
+		&models.Membership{},
+		&models.Category{},
+		&models.Tag{},
+		&models.ProductImage{},
+		&models.ProductVariant{},
+		&models.StockMovement{},
+		&models.StockReservation{},
+		&models.ProductPrice{},
+		&models.PriceChange{},
+		&models.ScheduledPrice{},
+		&models.Cart{},
+		&models.CartItem{},
+		&models.Order{},
+		&models.OrderItem{},
+		&models.OrderStatusChange{},
+	); err != nil {
+		return err
+	}
+
+	if err := migrateMoney(db, cfg.DefaultCurrency); err != nil {
+		return err
+	}
+
+	// 每个产品每种货币最多一条价格
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_product_prices_currency ON product_prices (product_id, price_currency)").Error; err != nil {
+		return err
+	}
+
+	if err := migrateProductSearch(db); err != nil {
+		return err
+	}
+
+	// 升级前的待支付订单没有过期时间，从现在起按 ORDER_PENDING_TTL 计算
+	if err := db.Exec("UPDATE orders SET expires_at = NOW() + make_interval(secs => ?) WHERE status = ? AND expires_at IS NULL",
+		cfg.OrderPendingTTL.Seconds(), models.OrderPending).Error; err != nil {
+		return err
+	}
+
+	// 分类按物化路径前缀查询子孙分类
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_categories_path ON categories (path text_pattern_ops)").Error; err != nil {
+		return err
+	}
+
+	// 每个产品最多一张主图
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_product_images_primary ON product_images (product_id) WHERE is_primary").Error; err != nil {
+		return err
+	}
+
+	// 写入权限目录和内置角色
+	if err := services.NewRBACService(db).SeedDefaults(); err != nil {
+		return err
+	}
+
+	// 创建默认组织并归档升级前的数据
+	return services.NewOrganizationService(db).EnsureDefault()
+}
+
+// migrateMoney 把升级前以浮点数保存的价格列换算为最小货币单位，货币记为默认货币，然后删除旧列
+func migrateMoney(db *gorm.DB, currency string) error {
+	scale, err := money.Scale(currency)
+	if err != nil {
+		return err
+	}
+	factor := int64(math.Pow10(scale))
+
+	columns := []struct{ table, column string }{
+		{"products", "price"},
+		{"products", "max_price"},
+		{"product_variants", "price"},
+	}
+	migrator := db.Migrator()
+	// 更早的版本没有最高价格，与价格相同
+	if migrator.HasColumn("products", "price") && migrator.HasColumn("products", "max_price") {
+		if err := db.Exec("UPDATE products SET max_price = price WHERE max_price < price").Error; err != nil {
+			return err
+		}
+	}
+	for _, c := range columns {
+		if !migrator.HasColumn(c.table, c.column) {
+			continue
+		}
+		stmt := fmt.Sprintf("UPDATE %[1]s SET %[2]s_amount = ROUND(%[2]s::numeric * ?), %[2]s_currency = ? WHERE %[2]s_currency = ''", c.table, c.column)
+		if err := db.Exec(stmt, factor, currency).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", c.table, c.column)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateProductSearch 创建产品搜索使用的 tsvector 生成列、GIN 索引和 pg_trgm 扩展。
+// 生成列的文本搜索配置需要与 ProductService.SearchProducts 保持一致
+func migrateProductSearch(db *gorm.DB) error {
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+		`ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(description, '')), 'B')
+		) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector)`,
+		`CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (name gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_products_description_trgm ON products USING GIN (description gin_trgm_ops)`,
+	}
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // InitializeSuperAdmin 初始化超级管理员
-func InitializeSuperAdmin(db *gorm.DB) error {
+func InitializeSuperAdmin(db *gorm.DB, cfg *config.Config) error {
 	// 创建用户服务实例
-	userService := services.NewUserService(db)
+	userService := services.NewUserService(db, cfg)
 
 	return userService.CreateSuperAdmin()
 }
@@ -0,0 +1,163 @@
+package listing
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// cursor 游标内容：生成时的排序、边界行的排序键，以及翻页方向。
+// 编码为 base64 JSON，对客户端不透明
+type cursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+	Before bool              `json:"b,omitempty"`
+}
+
+func decodeCursor(raw string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+	return &c, nil
+}
+
+// findByCursor 键集分页：以游标中边界行的排序键为条件查询，不使用 OFFSET，
+// 翻页期间插入或删除数据也不会跳过或重复已有的行。多取一条用于判断当前方向上是否还有数据
+func findByCursor[T any](query *gorm.DB, p *Params, page *Page[T]) error {
+	fields, err := keysetFields(query, new(T), p.orders)
+	if err != nil {
+		return err
+	}
+
+	before := p.cursor != nil && p.cursor.Before
+	orders := make([]clause.OrderByColumn, len(p.orders))
+	for i, order := range p.orders {
+		orders[i] = order
+		// 向前翻页时倒序查询，取到结果后再反转
+		orders[i].Desc = order.Desc != before
+	}
+
+	if p.cursor != nil {
+		where, vars, err := keysetCondition(orders, fields, p.cursor.Values)
+		if err != nil {
+			return err
+		}
+		query = query.Where(where, vars...)
+	}
+
+	if err := query.Order(clause.OrderBy{Columns: orders}).Limit(p.Limit + 1).Find(&page.Items).Error; err != nil {
+		return err
+	}
+
+	more := len(page.Items) > p.Limit
+	if more {
+		page.Items = page.Items[:p.Limit]
+	}
+	if before {
+		slices.Reverse(page.Items)
+	}
+	if len(page.Items) == 0 {
+		return nil
+	}
+
+	// 向后翻页时，有游标说明前面还有数据；向前翻页时，来源页就是下一页
+	hasNext, hasPrev := more, p.cursor != nil
+	if before {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		if page.NextCursor, err = p.encodeCursor(fields, &page.Items[len(page.Items)-1], false); err != nil {
+			return err
+		}
+	}
+	if hasPrev {
+		if page.PrevCursor, err = p.encodeCursor(fields, &page.Items[0], true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keysetCondition 生成 (a, b, id) 在给定排序下位于边界行之后的条件：
+// a > ? OR (a = ? AND b > ?) OR (a = ? AND b = ? AND id > ?)，倒序的列使用 <
+func keysetCondition(orders []clause.OrderByColumn, fields []*schema.Field, raw []json.RawMessage) (string, []any, error) {
+	values := make([]any, len(fields))
+	for i, field := range fields {
+		value := reflect.New(field.FieldType)
+		if err := json.Unmarshal(raw[i], value.Interface()); err != nil {
+			return "", nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+		}
+		values[i] = value.Elem().Interface()
+	}
+
+	var (
+		terms []string
+		vars  []any
+	)
+	for i, order := range orders {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, orders[j].Column.Name+" = ?")
+			vars = append(vars, values[j])
+		}
+		op := " > ?"
+		if order.Desc {
+			op = " < ?"
+		}
+		parts = append(parts, order.Column.Name+op)
+		vars = append(vars, values[i])
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", vars, nil
+}
+
+// encodeCursor 以 row 的排序键生成游标
+func (p *Params) encodeCursor(fields []*schema.Field, row any, before bool) (string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(row))
+	c := cursor{Sort: p.sort, Values: make([]json.RawMessage, len(fields)), Before: before}
+	for i, field := range fields {
+		value, _ := field.ValueOf(context.Background(), rv)
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		c.Values[i] = data
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// keysetFields 排序列对应的模型字段，排序列必须是模型自身的字段
+func keysetFields(db *gorm.DB, model any, orders []clause.OrderByColumn) ([]*schema.Field, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	fields := make([]*schema.Field, len(orders))
+	for i, order := range orders {
+		name := order.Column.Name[strings.LastIndex(order.Column.Name, ".")+1:]
+		field := stmt.Schema.LookUpField(name)
+		if field == nil {
+			return nil, fmt.Errorf("listing: sort column %q is not a field of %s", order.Column.Name, stmt.Schema.Name)
+		}
+		fields[i] = field
+	}
+	return fields, nil
+}
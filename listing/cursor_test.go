@@ -0,0 +1,176 @@
+package listing
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type cursorItem struct {
+	ID        uint
+	Name      string
+	CreatedAt time.Time
+}
+
+var cursorSpec = Spec{
+	Sorts: map[string]string{
+		"id":         "cursor_items.id",
+		"name":       "cursor_items.name",
+		"created_at": "cursor_items.created_at",
+	},
+	DefaultSort: "-created_at",
+	TieBreaker:  "cursor_items.id",
+}
+
+// cursorFields 只解析模型、不连接数据库
+func cursorFields(t *testing.T, p *Params) []*schema.Field {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := keysetFields(db, &cursorItem{}, p.orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+func parseQuery(t *testing.T, values url.Values) (*Params, error) {
+	t.Helper()
+	return Parse(values, cursorSpec)
+}
+
+func encodeRaw(t *testing.T, c cursor) string {
+	t.Helper()
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	first, err := parseQuery(t, url.Values{"cursor": {""}, "sort": {"-created_at,name"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := cursorFields(t, first)
+
+	createdAt := time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC)
+	row := &cursorItem{ID: 42, Name: `O'Brien "x"`, CreatedAt: createdAt}
+
+	for _, before := range []bool{false, true} {
+		raw, err := first.encodeCursor(fields, row, before)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 后续请求可以省略 sort，排序从游标中恢复
+		next, err := parseQuery(t, url.Values{"cursor": {raw}})
+		if err != nil {
+			t.Fatalf("Parse(cursor): %v", err)
+		}
+		if next.sort != "-created_at,name" || next.cursor.Before != before {
+			t.Fatalf("decoded sort = %q, before = %v", next.sort, next.cursor.Before)
+		}
+		if !reflect.DeepEqual(next.orders, first.orders) {
+			t.Fatalf("orders = %+v, want %+v", next.orders, first.orders)
+		}
+
+		where, vars, err := keysetCondition(next.orders, fields, next.cursor.Values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantWhere := "((cursor_items.created_at < ?) OR (cursor_items.created_at = ? AND cursor_items.name > ?) OR " +
+			"(cursor_items.created_at = ? AND cursor_items.name = ? AND cursor_items.id > ?))"
+		if where != wantWhere {
+			t.Fatalf("where = %s", where)
+		}
+		wantVars := []any{createdAt, createdAt, row.Name, createdAt, row.Name, uint(42)}
+		if len(vars) != len(wantVars) {
+			t.Fatalf("vars = %v", vars)
+		}
+		for i := range vars {
+			if v, ok := vars[i].(time.Time); ok {
+				if !v.Equal(createdAt) {
+					t.Fatalf("vars[%d] = %v, want %v", i, v, createdAt)
+				}
+				continue
+			}
+			if vars[i] != wantVars[i] {
+				t.Fatalf("vars[%d] = %#v, want %#v", i, vars[i], wantVars[i])
+			}
+		}
+	}
+}
+
+// TestCursorTamperRejected 游标不签名：改写边界值等同于客户端自己提交过滤条件，
+// 但结构、排序和值类型被篡改的游标必须被拒绝，不能绕过排序白名单或生成错误的条件
+func TestCursorTamperRejected(t *testing.T) {
+	valid := func(v ...string) []json.RawMessage {
+		raw := make([]json.RawMessage, len(v))
+		for i, s := range v {
+			raw[i] = json.RawMessage(s)
+		}
+		return raw
+	}
+	ts := `"2026-03-01T12:30:00Z"`
+
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"not base64", url.Values{"cursor": {"%%%"}}},
+		{"not json", url.Values{"cursor": {base64.RawURLEncoding.EncodeToString([]byte("{"))}}},
+		{"truncated", url.Values{"cursor": {encodeRaw(t, cursor{Sort: "id", Values: valid("1")})[:10]}}},
+		{"sort not allowed", url.Values{"cursor": {encodeRaw(t, cursor{Sort: "password", Values: valid(`"x"`, "1")})}}},
+		{"sort changed in request", url.Values{
+			"cursor": {encodeRaw(t, cursor{Sort: "-created_at", Values: valid(ts, "1")})},
+			"sort":   {"name"},
+		}},
+		{"too few values", url.Values{"cursor": {encodeRaw(t, cursor{Sort: "-created_at", Values: valid(ts)})}}},
+		{"too many values", url.Values{"cursor": {encodeRaw(t, cursor{Sort: "-created_at", Values: valid(ts, "1", "2")})}}},
+		{"combined with page", url.Values{"cursor": {encodeRaw(t, cursor{Sort: "id", Values: valid("1")})}, "page": {"2"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseQuery(t, tt.query); !errors.Is(err, ErrInvalidQuery) {
+				t.Fatalf("Parse: got %v, want ErrInvalidQuery", err)
+			}
+		})
+	}
+
+	// 值类型与排序列不符时在生成条件时拒绝
+	typeTests := []struct {
+		name   string
+		values []json.RawMessage
+	}{
+		{"string for time", valid(`"yesterday"`, "1")},
+		{"object for time", valid(`{"$gt":0}`, "1")},
+		{"string for id", valid(ts, `"1 OR 1=1"`)},
+		{"negative id", valid(ts, "-1")},
+	}
+	for _, tt := range typeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := parseQuery(t, url.Values{"cursor": {encodeRaw(t, cursor{Sort: "-created_at", Values: tt.values})}})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if _, _, err := keysetCondition(p.orders, cursorFields(t, p), p.cursor.Values); !errors.Is(err, ErrInvalidQuery) {
+				t.Fatalf("keysetCondition: got %v, want ErrInvalidQuery", err)
+			}
+		})
+	}
+}
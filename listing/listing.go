@@ -0,0 +1,316 @@
+package listing
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var ErrInvalidQuery = errors.New("invalid query parameter")
+
+// Kind 过滤参数的值类型
+type Kind int
+
+const (
+	String Kind = iota
+	Uint
+	Int
+	Float
+	Bool
+	Time
+	Keywords // 逗号分隔的关键字列表，去除空白、转为小写并去重
+)
+
+// Filter 列表接口支持的过滤参数。Where 为带一个 ? 占位符的 SQL 条件；
+// 条件需要多个参数时由 Args 根据解析后的值生成。Requires 为使用该过滤时必须同时提交的参数
+type Filter struct {
+	Param    string
+	Kind     Kind
+	Where    string
+	Args     func(value any) []any
+	Requires string
+}
+
+// Spec 列表接口允许的过滤和排序字段
+type Spec struct {
+	Filters      []Filter
+	Sorts        map[string]string // 排序参数名 -> 数据库列
+	DefaultSort  string            // 默认排序，格式与 sort 参数相同
+	TieBreaker   string            // 追加在排序最后的唯一列，保证分页顺序稳定
+	SortRequires map[string]string // 排序参数名 -> 按该字段排序时必须同时提交的参数
+}
+
+type condition struct {
+	where string
+	args  []any
+}
+
+// Params 解析后的列表参数
+type Params struct {
+	Limit  int
+	Offset int
+
+	paged      bool
+	cursorMode bool
+	cursor     *cursor
+	sort       string
+	orders     []clause.OrderByColumn
+	conditions []condition
+}
+
+// Page 一页查询结果
+type Page[T any] struct {
+	Items      []T
+	Total      int64
+	NextCursor string
+	PrevCursor string
+}
+
+// Parse 解析分页、过滤和排序参数。分页支持三种写法：page/page_size、limit/offset，
+// 以及游标分页 cursor/limit（cursor 为空表示第一页，之后使用响应中的 next_cursor / prev_cursor）。
+// sort 为逗号分隔的字段列表，字段前加 - 表示倒序，例如 sort=-price,name
+func Parse(values url.Values, spec Spec) (*Params, error) {
+	p := &Params{Limit: DefaultPageSize}
+
+	usesPage := values.Has("page") || values.Has("page_size")
+	usesOffset := values.Has("offset")
+	p.cursorMode = values.Has("cursor")
+	switch {
+	case p.cursorMode && (usesPage || usesOffset):
+		return nil, fmt.Errorf("%w: cursor cannot be combined with page, page_size or offset", ErrInvalidQuery)
+	case usesPage && (usesOffset || values.Has("limit")):
+		return nil, fmt.Errorf("%w: use either page/page_size or limit/offset", ErrInvalidQuery)
+	}
+	p.paged = usesPage || !(usesOffset || values.Has("limit"))
+
+	if p.paged {
+		page, err := intParam(values, "page", 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		size, err := intParam(values, "page_size", DefaultPageSize, 1)
+		if err != nil {
+			return nil, err
+		}
+		p.Limit = min(size, MaxPageSize)
+		p.Offset = (page - 1) * p.Limit
+	} else {
+		limit, err := intParam(values, "limit", DefaultPageSize, 1)
+		if err != nil {
+			return nil, err
+		}
+		offset, err := intParam(values, "offset", 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		p.Limit, p.Offset = min(limit, MaxPageSize), offset
+	}
+
+	p.sort = values.Get("sort")
+	if raw := values.Get("cursor"); raw != "" {
+		c, err := decodeCursor(raw)
+		if err != nil {
+			return nil, err
+		}
+		// 游标与生成它的排序绑定，后续请求可以省略 sort
+		if p.sort != "" && p.sort != c.Sort {
+			return nil, fmt.Errorf("%w: cursor was created with a different sort", ErrInvalidQuery)
+		}
+		p.cursor, p.sort = c, c.Sort
+	}
+	if p.sort == "" {
+		p.sort = spec.DefaultSort
+	}
+
+	for _, field := range strings.Split(p.sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		desc := strings.HasPrefix(field, "-")
+		name := strings.TrimPrefix(field, "-")
+		column, ok := spec.Sorts[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, name)
+		}
+		if required := spec.SortRequires[name]; required != "" && values.Get(required) == "" {
+			return nil, fmt.Errorf("%w: sorting by %s requires %s", ErrInvalidQuery, name, required)
+		}
+		p.orders = append(p.orders, clause.OrderByColumn{Column: clause.Column{Name: column, Raw: true}, Desc: desc})
+	}
+	if spec.TieBreaker != "" && !slices.ContainsFunc(p.orders, func(o clause.OrderByColumn) bool { return o.Column.Name == spec.TieBreaker }) {
+		p.orders = append(p.orders, clause.OrderByColumn{Column: clause.Column{Name: spec.TieBreaker, Raw: true}})
+	}
+	if p.cursor != nil && len(p.cursor.Values) != len(p.orders) {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	for _, f := range spec.Filters {
+		raw := values.Get(f.Param)
+		if raw == "" {
+			continue
+		}
+		if f.Requires != "" && values.Get(f.Requires) == "" {
+			return nil, fmt.Errorf("%w: %s requires %s", ErrInvalidQuery, f.Param, f.Requires)
+		}
+		value, err := parseValue(f.Kind, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidQuery, f.Param, err)
+		}
+		args := []any{value}
+		if f.Args != nil {
+			args = f.Args(value)
+		}
+		p.conditions = append(p.conditions, condition{where: f.Where, args: args})
+	}
+
+	return p, nil
+}
+
+// Query 统计符合过滤条件的总数并查询当前页，preloads 为查询当前页时需要预加载的关联
+func Query[T any](db *gorm.DB, p *Params, preloads ...string) (*Page[T], error) {
+	page := &Page[T]{Items: []T{}}
+	if err := p.Filter(db.Model(new(T))).Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+
+	query := p.Filter(db)
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	if p.cursorMode {
+		if err := findByCursor(query, p, page); err != nil {
+			return nil, err
+		}
+		return page, nil
+	}
+
+	if len(p.orders) > 0 {
+		query = query.Order(clause.OrderBy{Columns: p.orders})
+	}
+	if err := query.Limit(p.Limit).Offset(p.Offset).Find(&page.Items).Error; err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// Filter 追加过滤条件
+func (p *Params) Filter(db *gorm.DB) *gorm.DB {
+	for _, c := range p.conditions {
+		db = db.Where(c.where, c.args...)
+	}
+	return db
+}
+
+// UsesCursor 请求是否使用游标分页
+func (p *Params) UsesCursor() bool {
+	return p.cursorMode
+}
+
+// Page 当前页码（从 1 开始），游标分页时为 0
+func (p *Params) Page() int {
+	if p.cursorMode {
+		return 0
+	}
+	return p.Offset/p.Limit + 1
+}
+
+// Links 根据当前请求地址生成下一页和上一页链接，没有对应页时返回空字符串。
+// 链接沿用请求使用的分页写法，并保留其他查询参数
+func (p *Params) Links(u *url.URL, total int64, nextCursor, prevCursor string) (next, prev string) {
+	if p.cursorMode {
+		if nextCursor != "" {
+			next = p.cursorLink(u, nextCursor)
+		}
+		if prevCursor != "" {
+			prev = p.cursorLink(u, prevCursor)
+		}
+		return next, prev
+	}
+
+	if int64(p.Offset+p.Limit) < total {
+		next = p.link(u, p.Offset+p.Limit)
+	}
+	if p.Offset > 0 {
+		prev = p.link(u, max(p.Offset-p.Limit, 0))
+	}
+	return next, prev
+}
+
+func (p *Params) link(u *url.URL, offset int) string {
+	values := u.Query()
+	if p.paged && offset%p.Limit == 0 {
+		values.Set("page", strconv.Itoa(offset/p.Limit+1))
+		values.Set("page_size", strconv.Itoa(p.Limit))
+	} else {
+		values.Del("page")
+		values.Del("page_size")
+		values.Set("limit", strconv.Itoa(p.Limit))
+		values.Set("offset", strconv.Itoa(offset))
+	}
+	return u.Path + "?" + values.Encode()
+}
+
+func (p *Params) cursorLink(u *url.URL, c string) string {
+	values := u.Query()
+	values.Set("cursor", c)
+	values.Set("limit", strconv.Itoa(p.Limit))
+	return u.Path + "?" + values.Encode()
+}
+
+func intParam(values url.Values, name string, def, minimum int) (int, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < minimum {
+		return 0, fmt.Errorf("%w: %s must be an integer >= %d", ErrInvalidQuery, name, minimum)
+	}
+	return n, nil
+}
+
+// parseValue 时间接受 RFC3339 或 YYYY-MM-DD 格式
+func parseValue(kind Kind, raw string) (any, error) {
+	switch kind {
+	case Uint:
+		return strconv.ParseUint(raw, 10, 32)
+	case Int:
+		return strconv.ParseInt(raw, 10, 64)
+	case Float:
+		return strconv.ParseFloat(raw, 64)
+	case Bool:
+		return strconv.ParseBool(raw)
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.DateOnly, raw)
+	case Keywords:
+		var keywords []string
+		for _, k := range strings.Split(raw, ",") {
+			k = strings.ToLower(strings.TrimSpace(k))
+			if k != "" && !slices.Contains(keywords, k) {
+				keywords = append(keywords, k)
+			}
+		}
+		if len(keywords) == 0 {
+			return nil, errors.New("at least one value is required")
+		}
+		return keywords, nil
+	default:
+		return raw, nil
+	}
+}
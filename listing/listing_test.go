@@ -0,0 +1,48 @@
+package listing
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+var testSpec = Spec{
+	Filters: []Filter{
+		{Param: "min_price", Kind: Float, Where: "price >= ?", Requires: "currency"},
+		{Param: "currency", Kind: String, Where: "currency = ?"},
+	},
+	Sorts:        map[string]string{"id": "id", "price": "price"},
+	SortRequires: map[string]string{"price": "currency"},
+	DefaultSort:  "-id",
+	TieBreaker:   "id",
+}
+
+func TestParseRequires(t *testing.T) {
+	tests := []struct {
+		query string
+		ok    bool
+	}{
+		{"min_price=10", false},
+		{"min_price=10&currency=USD", true},
+		{"sort=-price", false},
+		{"sort=id,-price", false},
+		{"sort=-price&currency=USD", true},
+		{"sort=id", true},
+		{"currency=USD", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = Parse(values, testSpec)
+			if tt.ok && err != nil {
+				t.Fatalf("Parse(%q): %v", tt.query, err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidQuery) {
+				t.Fatalf("Parse(%q): got %v, want ErrInvalidQuery", tt.query, err)
+			}
+		})
+	}
+}
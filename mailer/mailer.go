@@ -0,0 +1,77 @@
+package mailer
+
+import (
+	"fmt"
+	"go-webapi-example/config"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Message 邮件消息
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送接口，可以替换为 SMTP 或第三方邮件服务的实现
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New 根据配置创建邮件发送器
+func New(cfg *config.Config) Mailer {
+	switch cfg.MailerDriver {
+	case "file":
+		return &FileMailer{Dir: cfg.MailerDir, From: cfg.MailFrom}
+	default:
+		return &LogMailer{From: cfg.MailFrom}
+	}
+}
+
+// LogMailer 将邮件内容输出到日志，适合本地开发
+type LogMailer struct {
+	From string
+}
+
+func (m *LogMailer) Send(msg Message) error {
+	log.Printf("[mailer] from=%s to=%s subject=%q\n%s", m.From, msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// FileMailer 将每封邮件写入目录中的 .eml 文件，便于在本地查看
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s_%s.eml", now.Format("20060102T150405.000000000"), sanitizeFileName(msg.To))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	return os.WriteFile(filepath.Join(m.Dir, name), []byte(b.String()), 0o644)
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, s)
+}
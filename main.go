@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"go-webapi-example/config"
 	"go-webapi-example/database"
 	"go-webapi-example/routes"
+	"go-webapi-example/services"
+	"go-webapi-example/utils"
 	"log"
 
 	"github.com/gin-gonic/gin"
@@ -23,6 +26,10 @@ import (
 // @in header
 // @name Authorization
 // @description Bearer token for authentication
+// @securityDefinitions.apikey APIKeyHeader
+// @in header
+// @name X-API-Key
+// @description Personal API key (alternatively send "Authorization: ApiKey {key}")
 func main() {
 	// 加载环境变量
 	if err := godotenv.Load(); err != nil {
@@ -32,25 +39,39 @@ func main() {
 	// 初始化配置
 	cfg := config.Load()
 
+	// 初始化 JWT 签名密钥
+	if err := utils.InitJWT(cfg); err != nil {
+		log.Fatal("Failed to initialize JWT keys:", err)
+	}
+
 	// 初始化数据库
 	db, err := database.Initialize(cfg)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 	// 运行数据迁移
-	if err := database.Migrate(db); err != nil {
+	if err := database.Migrate(db, cfg); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
 	// 初始化超级管理员
-	if err := database.InitializeSuperAdmin(db); err != nil {
+	if err := database.InitializeSuperAdmin(db, cfg); err != nil {
 		log.Printf("Warning: Failed to initialize super admin: %v", err)
 	} else {
 		log.Println("Super admin initialized successfully")
 	}
 
+	// 启动后台任务
+	go services.NewReservationService(db, cfg).RunSweeper(context.Background())
+	go services.NewPriceHistoryService(db, cfg).RunScheduler(context.Background())
+	go services.NewOrderService(db, cfg).RunSweeper(context.Background())
+
 	// 初始化 Gin 路由
 	r := gin.Default()
+	// 默认不信任任何代理，客户端 IP 取连接的对端地址，防止伪造 X-Forwarded-For 绕过按 IP 的登录限流
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
@@ -61,7 +82,7 @@ func main() {
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Org-ID, X-Cart-Token")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -71,7 +92,7 @@ func main() {
 	})
 
 	// 设置路由
-	routes.SetupRoutes(r, db)
+	routes.SetupRoutes(r, db, cfg)
 
 	// Swagger 文档
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
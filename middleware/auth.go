@@ -1,16 +1,36 @@
 package middleware
 
 import (
+	"errors"
+	"go-webapi-example/config"
+	"go-webapi-example/services"
 	"go-webapi-example/utils"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-// AuthMiddleware JWT 认证中间件
-func AuthMiddleware() gin.HandlerFunc {
+// 认证方式，保存在上下文的 authMethod 中
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
+
+// AuthMiddleware 认证中间件，支持 Bearer JWT 和个人 API 密钥（X-API-Key 头或 Authorization: ApiKey {key}）
+func AuthMiddleware(db *gorm.DB) gin.HandlerFunc {
+	revocationService := services.NewRevocationService(db)
+	apiKeyService := services.NewAPIKeyService(db)
+	rbacService := services.NewRBACService(db)
+
 	return func(c *gin.Context) {
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			authenticateAPIKey(c, apiKeyService, rbacService, apiKey)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
@@ -18,10 +38,14 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Bearer token
+		// Bearer token 或 ApiKey
 		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) == 2 && parts[0] == "ApiKey" {
+			authenticateAPIKey(c, apiKeyService, rbacService, parts[1])
+			return
+		}
 		if !(len(parts) == 2 && parts[0] == "Bearer") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer {token}"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer {token} or ApiKey {key}"})
 			c.Abort()
 			return
 		}
@@ -33,26 +57,89 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 检查令牌是否已被撤销（登出、用户删除、停用或角色变更）
+		revoked, err := revocationService.IsRevoked(claims)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify token"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
+
+		// 权限随访问令牌签发；升级前签发的令牌没有权限列表，按角色实时查询
+		permissions := claims.Permissions
+		if permissions == nil {
+			if permissions, err = rbacService.PermissionsForRole(claims.Role); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load permissions"})
+				c.Abort()
+				return
+			}
+		}
+
 		// 将用户信息设置到上下文中
+		c.Set("authMethod", AuthMethodJWT)
+		c.Set("claims", claims)
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
 		c.Set("userRole", claims.Role)
+		c.Set("permissions", permissions)
 		c.Next()
 	}
 }
 
-// AdminMiddleware 管理员权限中间件
-func AdminMiddleware() gin.HandlerFunc {
+// authenticateAPIKey 校验 API 密钥，并以密钥所属用户的身份继续处理请求
+func authenticateAPIKey(c *gin.Context, apiKeyService *services.APIKeyService, rbacService *services.RBACService, rawKey string) {
+	user, apiKey, err := apiKeyService.Authenticate(strings.TrimSpace(rawKey))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAPIKey) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify API key"})
+		}
+		c.Abort()
+		return
+	}
+
+	// API 密钥没有签发时的权限快照，按用户当前角色查询
+	permissions, err := rbacService.PermissionsForRole(user.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load permissions"})
+		c.Abort()
+		return
+	}
+
+	c.Set("authMethod", AuthMethodAPIKey)
+	c.Set("apiKeyID", apiKey.ID)
+	c.Set("userID", user.ID)
+	c.Set("userEmail", user.Email)
+	c.Set("userRole", user.Role)
+	c.Set("permissions", permissions)
+	c.Next()
+}
+
+// TwoFactorMiddleware 二步验证强制中间件：配置为必须启用二步验证的角色，只有通过二步验证登录后才能访问
+func TwoFactorMiddleware(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("userRole")
+		// API 密钥只能在已登录（并满足二步验证要求）的会话中创建，不再重复校验
+		if c.GetString("authMethod") == AuthMethodAPIKey {
+			c.Next()
+			return
+		}
+
+		value, exists := c.Get("claims")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 			c.Abort()
 			return
 		}
 
-		if role != "admin" && role != "superadmin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		claims := value.(*utils.Claims)
+		if !claims.MFA && slices.Contains(cfg.TwoFactorRequiredRoles, claims.Role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication is required for your role, enable it via /api/v1/users/profile/2fa and login again"})
 			c.Abort()
 			return
 		}
@@ -61,18 +148,18 @@ func AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
-// SuperAdminMiddleware 超级管理员权限中间件
-func SuperAdminMiddleware() gin.HandlerFunc {
+// RequirePermission 权限校验中间件，当前用户的角色必须拥有指定权限
+func RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("userRole")
+		value, exists := c.Get("permissions")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User permissions not found"})
 			c.Abort()
 			return
 		}
 
-		if role != "superadmin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Super admin access required"})
+		if !slices.Contains(value.([]string), permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Permission " + permission + " required"})
 			c.Abort()
 			return
 		}
@@ -80,3 +167,18 @@ func SuperAdminMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// HasPermission 判断当前请求的用户是否拥有指定权限
+func HasPermission(c *gin.Context, permission string) bool {
+	value, exists := c.Get("permissions")
+	return exists && slices.Contains(value.([]string), permission)
+}
+
+// CurrentActor 返回当前请求的用户及其权限，供服务层做资源归属校验
+func CurrentActor(c *gin.Context) *services.Actor {
+	actor := &services.Actor{UserID: c.GetUint("userID"), Role: c.GetString("userRole")}
+	if value, exists := c.Get("permissions"); exists {
+		actor.Permissions = value.([]string)
+	}
+	return actor
+}
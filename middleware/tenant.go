@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"errors"
+	"go-webapi-example/models"
+	"go-webapi-example/services"
+	"go-webapi-example/tenant"
+	"go-webapi-example/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TenantMiddleware 租户中间件，必须在 AuthMiddleware 之后使用。
+// 当前组织依次取自 X-Org-ID 请求头、访问令牌中的 org_id、用户最早加入的组织；
+// 用户必须是该组织成员（拥有 organizations:manage 权限的用户可以访问任意组织）
+func TenantMiddleware(db *gorm.DB) gin.HandlerFunc {
+	organizationService := services.NewOrganizationService(db)
+
+	return func(c *gin.Context) {
+		userID := c.GetUint("userID")
+
+		var orgID uint
+		if header := c.GetHeader("X-Org-ID"); header != "" {
+			id, err := strconv.ParseUint(header, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid X-Org-ID header"})
+				c.Abort()
+				return
+			}
+			orgID = uint(id)
+		} else if value, exists := c.Get("claims"); exists && value.(*utils.Claims).OrganizationID != 0 {
+			orgID = value.(*utils.Claims).OrganizationID
+		} else {
+			id, err := organizationService.DefaultOrganizationID(userID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve organization"})
+				c.Abort()
+				return
+			}
+			orgID = id
+		}
+
+		if orgID == 0 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not a member of any organization"})
+			c.Abort()
+			return
+		}
+
+		var orgRole string
+		membership, err := organizationService.GetMembership(orgID, userID)
+		switch {
+		case err == nil:
+			orgRole = membership.Role
+		case errors.Is(err, gorm.ErrRecordNotFound) && HasPermission(c, models.PermOrgsManage):
+			if _, err := organizationService.GetOrganizationByID(orgID); err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+				c.Abort()
+				return
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not a member of this organization"})
+			c.Abort()
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve organization"})
+			c.Abort()
+			return
+		}
+
+		// 组织ID同时写入请求上下文，数据库查询据此自动按组织过滤
+		c.Set("orgID", orgID)
+		c.Set("orgRole", orgRole)
+		c.Request = c.Request.WithContext(tenant.WithOrganization(c.Request.Context(), orgID))
+		c.Next()
+	}
+}
+
+// GuestTenantMiddleware 匿名访问的租户中间件，组织必须通过 X-Org-ID 请求头指定，且开放了匿名店面（public_storefront）。
+// 未开放的组织与不存在的组织返回相同的 404，避免匿名访客探测组织
+func GuestTenantMiddleware(db *gorm.DB) gin.HandlerFunc {
+	organizationService := services.NewOrganizationService(db)
+
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.GetHeader("X-Org-ID"), 10, 32)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "X-Org-ID header is required"})
+			c.Abort()
+			return
+		}
+		org, err := organizationService.GetOrganizationByID(uint(id))
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve organization"})
+			c.Abort()
+			return
+		}
+		if err != nil || !org.PublicStorefront {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+			c.Abort()
+			return
+		}
+
+		orgID := uint(id)
+		c.Set("orgID", orgID)
+		c.Request = c.Request.WithContext(tenant.WithOrganization(c.Request.Context(), orgID))
+		c.Next()
+	}
+}
@@ -0,0 +1,189 @@
+package models
+
+import (
+	"time"
+)
+
+// RefreshToken 刷新令牌（服务端只保存哈希值）
+type RefreshToken struct {
+	ID           uint       `gorm:"primarykey" json:"id"`            // 令牌ID
+	CreatedAt    time.Time  `json:"created_at"`                      // 创建时间
+	UserID       uint       `gorm:"index;not null" json:"user_id"`   // 所属用户ID
+	TokenHash    string     `gorm:"uniqueIndex;not null" json:"-"`   // 令牌哈希
+	FamilyID     string     `gorm:"index;not null" json:"-"`         // 令牌族ID（同一次登录轮换出的令牌属于同一族）
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`      // 过期时间
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`            // 撤销（或被轮换）时间
+	ReplacedByID *uint      `json:"replaced_by_id,omitempty"`        // 轮换后的新令牌ID
+	MFA          bool       `gorm:"not null;default:false" json:"-"` // 该登录会话是否通过了二步验证
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"3q2-7wEAAAB..."` // 刷新令牌
+}
+
+// TokenResponse 令牌响应
+type TokenResponse struct {
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // JWT访问令牌
+	RefreshToken string `json:"refresh_token" example:"3q2-7wEAAAB..."`                  // 新的刷新令牌
+	ExpiresIn    int64  `json:"expires_in" example:"900"`                                // 访问令牌有效期（秒）
+}
+
+// RevokedToken 已撤销的访问令牌（按 jti 记录，过期后可清理）
+type RevokedToken struct {
+	ID        uint      `gorm:"primarykey" json:"id"`             // 记录ID
+	CreatedAt time.Time `json:"created_at"`                       // 撤销时间
+	JTI       string    `gorm:"uniqueIndex;not null" json:"jti"`  // 令牌ID
+	UserID    uint      `gorm:"index;not null" json:"user_id"`    // 所属用户ID
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"` // 令牌原过期时间
+}
+
+// LogoutRequest 登出请求
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty" example:"3q2-7wEAAAB..."` // 同时撤销的刷新令牌（可选）
+	AllDevices   bool   `json:"all_devices,omitempty" example:"false"`            // 是否登出所有设备（可选）
+}
+
+// ResendVerificationRequest 重新发送验证邮件请求
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email" example:"user@example.com"` // 邮箱地址
+}
+
+// RecoveryCode 二步验证恢复码（一次性使用，只保存哈希值）
+type RecoveryCode struct {
+	ID        uint       `gorm:"primarykey" json:"id"`          // 恢复码ID
+	CreatedAt time.Time  `json:"created_at"`                    // 创建时间
+	UserID    uint       `gorm:"index;not null" json:"user_id"` // 所属用户ID
+	CodeHash  string     `gorm:"index;not null" json:"-"`       // 恢复码哈希
+	UsedAt    *time.Time `json:"used_at,omitempty"`             // 使用时间
+}
+
+// TwoFactorSetupResponse 二步验证注册响应
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret" example:"JBSWY3DPEHPK3PXP"`                                                         // TOTP 密钥（可手动输入验证器应用）
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/WebAPI:user@example.com?secret=JBSWY3DPEHPK3PXP"` // 二维码内容
+}
+
+// TwoFactorCodeRequest 提交二步验证码请求
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"` // 验证器应用中的6位验证码
+}
+
+// DisableTwoFactorRequest 关闭二步验证请求
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required" example:"password123"` // 当前密码
+	Code     string `json:"code" binding:"required" example:"123456"`          // 验证码或恢复码
+}
+
+// TwoFactorLoginRequest 二步验证登录请求
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // 第一步登录返回的挑战令牌
+	Code           string `json:"code" binding:"required" example:"123456"`                                             // 验证码或恢复码
+	CartToken      string `json:"cart_token,omitempty" example:"3q2-7wEAAAB..."`                                        // 匿名购物车令牌（可选），登录成功后合并到用户的购物车
+}
+
+// RecoveryCodesResponse 恢复码响应（只展示一次）
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"abcde-fghij,klmno-pqrst"` // 一次性恢复码
+}
+
+// LoginAttempt 登录尝试记录，用于按账户和客户端 IP 统计失败次数
+type LoginAttempt struct {
+	ID        uint      `gorm:"primarykey" json:"id"`                  // 记录ID
+	CreatedAt time.Time `gorm:"index" json:"created_at"`               // 尝试时间
+	Email     string    `gorm:"index" json:"email"`                    // 登录邮箱
+	IP        string    `gorm:"index" json:"ip"`                       // 客户端 IP
+	UserID    *uint     `json:"user_id,omitempty"`                     // 匹配到的用户ID
+	Success   bool      `gorm:"not null;default:false" json:"success"` // 是否成功
+}
+
+// 锁定事件类型
+const (
+	LockoutEventAccountLocked = "account_locked"
+	LockoutEventIPBlocked     = "ip_blocked"
+	LockoutEventUnlocked      = "unlocked"
+)
+
+// LockoutEvent 账户锁定 / IP 封禁 / 解锁事件
+type LockoutEvent struct {
+	ID          uint       `gorm:"primarykey" json:"id" example:"1"`                    // 事件ID
+	CreatedAt   time.Time  `gorm:"index" json:"created_at"`                             // 事件时间
+	Type        string     `gorm:"index;not null" json:"type" example:"account_locked"` // 事件类型
+	UserID      *uint      `gorm:"index" json:"user_id,omitempty" example:"2"`          // 相关用户ID
+	Email       string     `json:"email,omitempty" example:"user@example.com"`          // 相关邮箱
+	IP          string     `json:"ip,omitempty" example:"203.0.113.7"`                  // 客户端 IP
+	Failures    int        `json:"failures" example:"5"`                                // 触发时的失败次数
+	LockedUntil *time.Time `json:"locked_until,omitempty"`                              // 锁定截止时间
+	ActorID     *uint      `json:"actor_id,omitempty" example:"1"`                      // 执行解锁的管理员ID
+}
+
+// APIKey 用户个人 API 密钥（服务端只保存哈希值，明文只在创建时展示一次）
+type APIKey struct {
+	ID         uint       `gorm:"primarykey" json:"id" example:"1"`                    // 密钥ID
+	CreatedAt  time.Time  `json:"created_at"`                                          // 创建时间
+	UpdatedAt  time.Time  `json:"updated_at"`                                          // 更新时间
+	UserID     uint       `gorm:"index;not null" json:"user_id" example:"1"`           // 所属用户ID
+	Name       string     `gorm:"not null" json:"name" example:"CI pipeline"`          // 密钥名称
+	Prefix     string     `gorm:"index;not null" json:"prefix" example:"wak_3q2-7wEA"` // 密钥前缀（用于识别）
+	KeyHash    string     `gorm:"uniqueIndex;not null" json:"-"`                       // 密钥哈希
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`                                // 过期时间（为空表示永不过期）
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`                              // 最近使用时间
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`                                // 撤销时间
+}
+
+// CreateAPIKeyRequest 创建 API 密钥请求
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100" example:"CI pipeline"` // 密钥名称
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2027-01-01T00:00:00Z"`   // 过期时间（可选）
+}
+
+// CreateAPIKeyResponse 创建 API 密钥响应
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key" example:"wak_3q2-7wEAAAB..."` // 密钥明文（只展示一次，请妥善保存）
+}
+
+// JWK 公开的 JSON Web Key
+type JWK struct {
+	Kty string `json:"kty" example:"RSA"`             // 密钥类型（RSA / EC）
+	Use string `json:"use" example:"sig"`             // 用途
+	Alg string `json:"alg" example:"RS256"`           // 签名算法
+	Kid string `json:"kid" example:"2026-01"`         // 密钥ID
+	N   string `json:"n,omitempty"`                   // RSA 模数
+	E   string `json:"e,omitempty" example:"AQAB"`    // RSA 指数
+	Crv string `json:"crv,omitempty" example:"P-256"` // EC 曲线
+	X   string `json:"x,omitempty"`                   // EC X 坐标
+	Y   string `json:"y,omitempty"`                   // EC Y 坐标
+}
+
+// JWKSet JSON Web Key 集合
+type JWKSet struct {
+	Keys []JWK `json:"keys"` // 公钥列表
+}
+
+// PasswordResetToken 密码重置令牌（一次性使用，服务端只保存哈希值）
+type PasswordResetToken struct {
+	ID        uint       `gorm:"primarykey" json:"id"`          // 令牌ID
+	CreatedAt time.Time  `json:"created_at"`                    // 创建时间
+	UserID    uint       `gorm:"index;not null" json:"user_id"` // 所属用户ID
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"` // 令牌哈希
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`    // 过期时间
+	UsedAt    *time.Time `json:"used_at,omitempty"`             // 使用时间
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"password123"`      // 当前密码
+	NewPassword     string `json:"new_password" binding:"required,min=6" example:"newpassword456"` // 新密码（至少6位）
+}
+
+// ForgotPasswordRequest 忘记密码请求
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"user@example.com"` // 邮箱地址
+}
+
+// ResetPasswordRequest 重置密码请求
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required" example:"3q2-7wEAAAB..."`              // 邮件中的重置令牌
+	NewPassword string `json:"new_password" binding:"required,min=6" example:"newpassword456"` // 新密码（至少6位）
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"go-webapi-example/money"
+	"time"
+)
+
+// 购物车行项目的问题，读取购物车时按实时价格和库存重新校验
+const (
+	CartIssueUnavailable       = "unavailable"        // 产品或变体已删除，或有变体的产品未指定变体
+	CartIssueInsufficientStock = "insufficient_stock" // 可售库存少于购买数量
+	CartIssuePriceChanged      = "price_changed"      // 价格自上次读取以来发生变化（仅提示，不影响结账）
+	CartIssuePriceUnavailable  = "price_unavailable"  // 无法以购物车货币报价（没有价格表也没有汇率）
+)
+
+// Cart 购物车，按组织隔离。登录用户在每个组织中有一个购物车；
+// 匿名购物车没有用户，通过创建时返回的购物车令牌访问，登录时合并到用户的购物车
+type Cart struct {
+	ID             uint        `gorm:"primarykey" json:"id" example:"1"`                                   // 购物车ID
+	CreatedAt      time.Time   `json:"created_at"`                                                         // 创建时间
+	UpdatedAt      time.Time   `json:"updated_at"`                                                         // 更新时间
+	OrganizationID uint        `gorm:"uniqueIndex:idx_cart_org_user;not null" json:"-"`                    // 所属组织ID
+	UserID         *uint       `gorm:"uniqueIndex:idx_cart_org_user" json:"user_id,omitempty" example:"1"` // 用户ID，匿名购物车为空
+	TokenHash      string      `gorm:"size:64;index" json:"-"`                                             // 匿名购物车令牌的哈希
+	CreatedIP      string      `gorm:"size:45;index" json:"-"`                                             // 创建匿名购物车的客户端 IP，用于限制创建频率
+	Token          string      `gorm:"-" json:"token,omitempty" example:"3q2-7wEAAAB..."`                  // 匿名购物车令牌，只在创建时返回
+	Currency       string      `gorm:"size:3;not null" json:"currency" example:"USD"`                      // 购物车货币，行项目价格按该货币报价
+	Items          []CartItem  `json:"items"`                                                              // 行项目
+	Subtotal       money.Money `gorm:"-" json:"subtotal"`                                                  // 可结账行项目的小计
+	ItemCount      int         `gorm:"-" json:"item_count" example:"3"`                                    // 商品总件数
+	CheckoutReady  bool        `gorm:"-" json:"checkout_ready" example:"true"`                             // 全部行项目都可以结账（没有 price_changed 以外的问题）
+}
+
+// CartItem 购物车行项目，同一购物车中同一产品（变体）只有一行
+type CartItem struct {
+	ID             uint         `gorm:"primarykey" json:"id" example:"1"`                  // 行项目ID
+	CreatedAt      time.Time    `json:"created_at"`                                        // 加入时间
+	UpdatedAt      time.Time    `json:"updated_at"`                                        // 更新时间
+	OrganizationID uint         `gorm:"index;not null" json:"-"`                           // 所属组织ID
+	CartID         uint         `gorm:"index;not null" json:"cart_id" example:"1"`         // 购物车ID
+	ProductID      uint         `gorm:"index;not null" json:"product_id" example:"1"`      // 产品ID
+	VariantID      *uint        `gorm:"index" json:"variant_id,omitempty" example:"3"`     // 变体ID，产品没有变体时为空
+	Quantity       int          `gorm:"not null" json:"quantity" example:"2"`              // 数量
+	Price          money.Money  `gorm:"embedded;embeddedPrefix:price_" json:"unit_price"`  // 单价（购物车货币），读取时更新为实时价格
+	Name           string       `gorm:"-" json:"name" example:"iPhone 15"`                 // 产品名称
+	SKU            string       `gorm:"-" json:"sku,omitempty" example:"IP15-128-BLK"`     // 变体 SKU
+	LineTotal      money.Money  `gorm:"-" json:"line_total"`                               // 小计：单价乘以数量
+	Available      int          `gorm:"-" json:"available" example:"98"`                   // 当前可售库存
+	PreviousPrice  *money.Money `gorm:"-" json:"previous_price,omitempty"`                 // 价格变化前的单价
+	Issues         []string     `gorm:"-" json:"issues,omitempty" example:"price_changed"` // 校验发现的问题
+}
+
+// CreateCartRequest 创建匿名购物车请求
+type CreateCartRequest struct {
+	Currency string `json:"currency,omitempty" example:"USD"` // 购物车货币（可选），默认 DEFAULT_CURRENCY
+}
+
+// AddCartItemRequest 加入购物车请求，购物车中已有同一产品（变体）时累加数量
+type AddCartItemRequest struct {
+	ProductID uint  `json:"product_id" binding:"required" example:"1"`             // 产品ID
+	VariantID *uint `json:"variant_id,omitempty" example:"3"`                      // 变体ID，产品有变体时必填
+	Quantity  int   `json:"quantity" binding:"required,min=1,max=999" example:"2"` // 数量
+}
+
+// UpdateCartItemRequest 修改行项目数量请求
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1,max=999" example:"3"` // 新数量
+}
+
+// MergeCartRequest 把匿名购物车合并到当前用户的购物车
+type MergeCartRequest struct {
+	CartToken string `json:"cart_token" binding:"required" example:"3q2-7wEAAAB..."` // 匿名购物车令牌
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Category 产品分类，按组织隔离。使用物化路径表示层级：Path 为从根分类到自身的ID，
+// 例如 /1/5/9/，查询子孙分类只需 path LIKE '/1/5/%'
+type Category struct {
+	ID             uint            `gorm:"primarykey" json:"id" example:"9"`                                              // 分类ID
+	CreatedAt      time.Time       `json:"created_at"`                                                                    // 创建时间
+	UpdatedAt      time.Time       `json:"updated_at"`                                                                    // 更新时间
+	OrganizationID uint            `gorm:"uniqueIndex:idx_category_org_slug;not null" json:"organization_id" example:"1"` // 所属组织ID
+	ParentID       *uint           `gorm:"index" json:"parent_id" example:"5"`                                            // 上级分类ID，根分类为空
+	Name           string          `gorm:"not null" json:"name" example:"智能手机"`                                           // 分类名称
+	Slug           string          `gorm:"uniqueIndex:idx_category_org_slug;not null" json:"slug" example:"smartphones"`  // 分类标识，组织内唯一
+	Path           string          `gorm:"not null" json:"path" example:"/1/5/9/"`                                        // 物化路径
+	Depth          int             `gorm:"not null;default:0" json:"depth" example:"2"`                                   // 层级深度，根分类为 0
+	Breadcrumbs    []CategoryCrumb `gorm:"-" json:"breadcrumbs,omitempty"`                                                // 从根分类到自身的路径
+	Children       []Category      `gorm:"-" json:"children,omitempty"`                                                   // 下级分类（分类树中返回）
+}
+
+// CategoryCrumb 面包屑中的一级分类
+type CategoryCrumb struct {
+	ID   uint   `json:"id" example:"1"`             // 分类ID
+	Name string `json:"name" example:"数码"`          // 分类名称
+	Slug string `json:"slug" example:"electronics"` // 分类标识
+}
+
+// CreateCategoryRequest 创建分类请求
+type CreateCategoryRequest struct {
+	Name     string `json:"name" binding:"required,max=100" example:"智能手机"`                  // 分类名称
+	Slug     string `json:"slug" binding:"required,max=100,lowercase" example:"smartphones"` // 分类标识
+	ParentID *uint  `json:"parent_id,omitempty" example:"5"`                                 // 上级分类ID（可选，不传为根分类）
+}
+
+// UpdateCategoryRequest 更新分类请求，parent_id 为 0 表示移动到根级
+type UpdateCategoryRequest struct {
+	Name     string `json:"name,omitempty" binding:"max=100" example:"手机"`               // 分类名称（可选）
+	Slug     string `json:"slug,omitempty" binding:"max=100,lowercase" example:"phones"` // 分类标识（可选）
+	ParentID *uint  `json:"parent_id,omitempty" example:"1"`                             // 新的上级分类ID（可选，0 为根级）
+}
+
+// SetProductCategoriesRequest 设置产品所属分类请求，整体替换
+type SetProductCategoriesRequest struct {
+	CategoryIDs []uint `json:"category_ids" binding:"required" example:"9,12"` // 分类ID列表，空列表表示清除全部分类
+}
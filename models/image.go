@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// ProductImage 产品图片，按组织隔离。原图和缩略图保存在文件存储中，数据库只记录存储 key 和访问地址
+type ProductImage struct {
+	ID             uint      `gorm:"primarykey" json:"id" example:"1"`                                          // 图片ID
+	CreatedAt      time.Time `json:"created_at"`                                                                // 上传时间
+	UpdatedAt      time.Time `json:"updated_at"`                                                                // 更新时间
+	OrganizationID uint      `gorm:"index;not null" json:"-"`                                                   // 所属组织ID
+	ProductID      uint      `gorm:"index;not null" json:"product_id" example:"1"`                              // 产品ID
+	Key            string    `gorm:"not null" json:"-"`                                                         // 原图存储 key
+	ThumbnailKey   string    `gorm:"not null" json:"-"`                                                         // 缩略图存储 key
+	URL            string    `gorm:"not null" json:"url" example:"/media/products/1/1/abc.jpg"`                 // 原图地址
+	ThumbnailURL   string    `gorm:"not null" json:"thumbnail_url" example:"/media/products/1/1/abc_thumb.jpg"` // 缩略图地址
+	ContentType    string    `gorm:"not null" json:"content_type" example:"image/jpeg"`                         // 图片类型
+	Size           int64     `json:"size" example:"204800"`                                                     // 原图大小（字节）
+	Width          int       `json:"width" example:"1200"`                                                      // 原图宽度（像素）
+	Height         int       `json:"height" example:"800"`                                                      // 原图高度（像素）
+	Position       int       `gorm:"not null;default:0" json:"position" example:"0"`                            // 排列顺序，从 0 开始
+	IsPrimary      bool      `gorm:"not null;default:false" json:"is_primary" example:"true"`                   // 是否为主图，每个产品最多一张
+}
+
+// ReorderProductImagesRequest 调整产品图片顺序请求，必须包含产品的全部图片
+type ReorderProductImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required,min=1" example:"3,1,2"` // 按新顺序排列的图片ID
+}
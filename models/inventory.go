@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// 库存变动类型
+const (
+	StockMovementReceive = "receive" // 入库
+	StockMovementSell    = "sell"    // 售出
+	StockMovementAdjust  = "adjust"  // 盘点调整
+	StockMovementReturn  = "return"  // 退货入库
+)
+
+// StockMovement 库存流水，每次库存变动记录一条，按组织隔离。
+// 有变体的产品记录到变体上，产品库存为变体库存之和
+type StockMovement struct {
+	ID             uint      `gorm:"primarykey" json:"id" example:"1"`               // 流水ID
+	CreatedAt      time.Time `gorm:"index" json:"created_at"`                        // 变动时间
+	OrganizationID uint      `gorm:"index;not null" json:"-"`                        // 所属组织ID
+	ProductID      uint      `gorm:"index;not null" json:"product_id" example:"1"`   // 产品ID
+	VariantID      *uint     `gorm:"index" json:"variant_id,omitempty" example:"3"`  // 变体ID，产品没有变体时为空
+	Type           string    `gorm:"size:20;not null" json:"type" example:"receive"` // 变动类型：receive / sell / adjust / return
+	Quantity       int       `gorm:"not null" json:"quantity" example:"10"`          // 变动数量，入库为正、出库为负
+	StockAfter     int       `gorm:"not null" json:"stock_after" example:"110"`      // 变动后的库存（有变体时为变体库存）
+	Reason         string    `gorm:"size:255" json:"reason" example:"供应商到货"`         // 变动原因
+	ActorID        *uint     `gorm:"index" json:"actor_id,omitempty" example:"1"`    // 操作用户ID，系统自动变动时为空
+}
+
+// StockAdjustmentRequest 增加或扣减库存请求。增加库存的类型为 receive、return 或 adjust，扣减库存的类型为 sell 或 adjust
+type StockAdjustmentRequest struct {
+	Quantity  int    `json:"quantity" binding:"required,min=1" example:"10"`                             // 数量（正数）
+	Type      string `json:"type" binding:"required,oneof=receive sell adjust return" example:"receive"` // 变动类型
+	Reason    string `json:"reason" binding:"max=255" example:"供应商到货"`                                   // 变动原因
+	VariantID *uint  `json:"variant_id,omitempty" example:"3"`                                           // 变体ID，产品有变体时必填
+}
+
+// StockMovementListResponse 库存流水列表响应
+type StockMovementListResponse struct {
+	Data       []StockMovement `json:"data"`       // 当前页流水
+	Pagination Pagination      `json:"pagination"` // 分页信息
+}
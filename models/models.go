@@ -1,6 +1,7 @@
 package models
 
 import (
+	"go-webapi-example/money"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,30 +9,52 @@ import (
 
 // User 用户模型
 type User struct {
-	ID        uint           `gorm:"primarykey" json:"id" example:"1"`                                                      // 用户ID
-	CreatedAt time.Time      `json:"created_at" example:"2023-01-01T00:00:00Z"`                                             // 创建时间
-	UpdatedAt time.Time      `json:"updated_at" example:"2023-01-01T00:00:00Z"`                                             // 更新时间
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`                                                                        // 删除时间（软删除）
-	Name      string         `gorm:"not null" json:"name" binding:"required" example:"张三"`                                  // 用户姓名
-	Email     string         `gorm:"uniqueIndex;not null" json:"email" binding:"required,email" example:"user@example.com"` // 邮箱地址
-	Password  string         `gorm:"not null" json:"-"`                                                                     // 密码（不在JSON中显示）
-	Age       int            `json:"age" binding:"min=0" example:"25"`                                                      // 年龄
-	Role      string         `gorm:"default:'user'" json:"role" example:"user"`                                             // 用户角色（user/admin/superadmin）
-	IsActive  bool           `gorm:"default:true" json:"is_active" example:"true"`                                          // 是否激活
+	ID                uint           `gorm:"primarykey" json:"id" example:"1"`                                                      // 用户ID
+	CreatedAt         time.Time      `json:"created_at" example:"2023-01-01T00:00:00Z"`                                             // 创建时间
+	UpdatedAt         time.Time      `json:"updated_at" example:"2023-01-01T00:00:00Z"`                                             // 更新时间
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`                                                                        // 删除时间（软删除）
+	Name              string         `gorm:"not null" json:"name" binding:"required" example:"张三"`                                  // 用户姓名
+	Email             string         `gorm:"uniqueIndex;not null" json:"email" binding:"required,email" example:"user@example.com"` // 邮箱地址
+	Password          string         `gorm:"not null" json:"-"`                                                                     // 密码（不在JSON中显示）
+	Age               int            `json:"age" binding:"min=0" example:"25"`                                                      // 年龄
+	Role              string         `gorm:"default:'user'" json:"role" example:"user"`                                             // 用户角色（角色名称，对应 roles 表）
+	IsActive          bool           `gorm:"default:true" json:"is_active" example:"true"`                                          // 是否激活
+	TokenVersion      int            `gorm:"not null;default:0" json:"-"`                                                           // 令牌版本（递增后旧令牌全部失效）
+	EmailVerified     bool           `gorm:"not null;default:false" json:"email_verified" example:"true"`                           // 邮箱是否已验证
+	EmailVerifiedAt   *time.Time     `json:"email_verified_at,omitempty"`                                                           // 邮箱验证时间
+	TwoFactorEnabled  bool           `gorm:"not null;default:false" json:"two_factor_enabled" example:"false"`                      // 是否启用二步验证
+	TOTPSecret        string         `json:"-"`                                                                                     // TOTP 密钥（启用前为待确认密钥）
+	TOTPLastStep      int64          `gorm:"not null;default:0" json:"-"`                                                           // 最近一次使用的 TOTP 时间步（防重放）
+	FailedLoginCount  int            `gorm:"not null;default:0" json:"-"`                                                           // 连续登录失败次数
+	LastFailedLoginAt *time.Time     `json:"-"`                                                                                     // 最近一次登录失败时间
+	LockedUntil       *time.Time     `json:"locked_until,omitempty"`                                                                // 账户锁定截止时间
+}
+
+// TenantCondition 用户通过 memberships 归属组织，按组织查询时只返回该组织的成员
+func (User) TenantCondition() string {
+	return "users.id IN (SELECT user_id FROM memberships WHERE organization_id = ?)"
 }
 
 // Product 产品模型
 type Product struct {
-	ID          uint           `gorm:"primarykey" json:"id" example:"1"`                                // 产品ID
-	CreatedAt   time.Time      `json:"created_at" example:"2023-01-01T00:00:00Z"`                       // 创建时间
-	UpdatedAt   time.Time      `json:"updated_at" example:"2023-01-01T00:00:00Z"`                       // 更新时间
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`                                                  // 删除时间（软删除）
-	Name        string         `gorm:"not null" json:"name" binding:"required" example:"iPhone 15"`     // 产品名称
-	Description string         `json:"description" example:"最新款智能手机"`                                   // 产品描述
-	Price       float64        `gorm:"not null" json:"price" binding:"required,min=0" example:"999.99"` // 产品价格
-	Stock       int            `gorm:"default:0" json:"stock" binding:"min=0" example:"100"`            // 库存数量
-	UserID      uint           `json:"user_id" example:"1"`                                             // 创建用户ID
-	User        User           `json:"user,omitempty"`                                                  // 关联用户信息
+	ID             uint             `gorm:"primarykey" json:"id" example:"1"`                            // 产品ID
+	CreatedAt      time.Time        `json:"created_at" example:"2023-01-01T00:00:00Z"`                   // 创建时间
+	UpdatedAt      time.Time        `json:"updated_at" example:"2023-01-01T00:00:00Z"`                   // 更新时间
+	DeletedAt      gorm.DeletedAt   `gorm:"index" json:"-"`                                              // 删除时间（软删除）
+	Name           string           `gorm:"not null" json:"name" binding:"required" example:"iPhone 15"` // 产品名称
+	Description    string           `json:"description" example:"最新款智能手机"`                               // 产品描述
+	Price          money.Money      `gorm:"embedded;embeddedPrefix:price_" json:"price"`                 // 产品价格，有变体时为最低变体价格
+	MaxPrice       money.Money      `gorm:"embedded;embeddedPrefix:max_price_" json:"max_price"`         // 最高价格，有变体时为最高变体价格，否则与价格相同
+	Stock          int              `gorm:"default:0" json:"stock" binding:"min=0" example:"100"`        // 库存数量，有变体时为全部变体库存之和
+	Available      int              `gorm:"-" json:"available" example:"98"`                             // 可售库存：库存减去预留中的数量
+	UserID         uint             `json:"user_id" example:"1"`                                         // 创建用户ID
+	OrganizationID uint             `gorm:"index" json:"organization_id" example:"1"`                    // 所属组织ID
+	User           User             `json:"user,omitempty"`                                              // 关联用户信息
+	Categories     []Category       `gorm:"many2many:product_categories;" json:"categories,omitempty"`   // 所属分类（含面包屑）
+	Tags           []Tag            `gorm:"many2many:product_tags;" json:"tags,omitempty"`               // 标签
+	Images         []ProductImage   `json:"images,omitempty"`                                            // 产品图片，按顺序排列
+	Variants       []ProductVariant `json:"variants,omitempty"`                                          // 产品变体，按顺序排列
+	Prices         []ProductPrice   `json:"prices,omitempty"`                                            // 价格表：其他货币下单独设定的价格
 }
 
 // CreateUserRequest 创建用户请求
@@ -45,24 +68,46 @@ type CreateUserRequest struct {
 
 // LoginRequest 登录请求
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email" example:"admin@example.com"` // 邮箱地址
-	Password string `json:"password" binding:"required" example:"admin123456"`          // 密码
+	Email     string `json:"email" binding:"required,email" example:"admin@example.com"` // 邮箱地址
+	Password  string `json:"password" binding:"required" example:"admin123456"`          // 密码
+	CartToken string `json:"cart_token,omitempty" example:"3q2-7wEAAAB..."`              // 匿名购物车令牌（可选），登录成功后合并到用户的购物车
 }
 
-// LoginResponse 登录响应
+// LoginResponse 登录响应。启用二步验证的账户第一步只返回挑战令牌，需调用 /auth/login/2fa 完成登录
 type LoginResponse struct {
-	Token string      `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // JWT访问令牌
-	User  UserProfile `json:"user"`                                                    // 用户信息
+	Token                  string       `json:"token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`           // JWT访问令牌
+	RefreshToken           string       `json:"refresh_token,omitempty" example:"3q2-7wEAAAB..."`                            // 刷新令牌
+	ExpiresIn              int64        `json:"expires_in,omitempty" example:"900"`                                          // 访问令牌有效期（秒）
+	User                   *UserProfile `json:"user,omitempty"`                                                              // 用户信息
+	TwoFactorRequired      bool         `json:"two_factor_required,omitempty" example:"false"`                               // 是否需要提交二步验证码
+	ChallengeToken         string       `json:"challenge_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // 二步验证挑战令牌
+	TwoFactorSetupRequired bool         `json:"two_factor_setup_required,omitempty" example:"false"`                         // 账户角色要求启用二步验证但尚未启用
 }
 
 // UserProfile 用户资料（不包含敏感信息）
 type UserProfile struct {
-	ID       uint   `json:"id" example:"1"`                   // 用户ID
-	Name     string `json:"name" example:"张三"`                // 用户姓名
-	Email    string `json:"email" example:"user@example.com"` // 邮箱地址
-	Age      int    `json:"age" example:"25"`                 // 年龄
-	Role     string `json:"role" example:"user"`              // 用户角色
-	IsActive bool   `json:"is_active" example:"true"`         // 是否激活
+	ID               uint   `json:"id" example:"1"`                     // 用户ID
+	Name             string `json:"name" example:"张三"`                  // 用户姓名
+	Email            string `json:"email" example:"user@example.com"`   // 邮箱地址
+	Age              int    `json:"age" example:"25"`                   // 年龄
+	Role             string `json:"role" example:"user"`                // 用户角色
+	IsActive         bool   `json:"is_active" example:"true"`           // 是否激活
+	EmailVerified    bool   `json:"email_verified" example:"true"`      // 邮箱是否已验证
+	TwoFactorEnabled bool   `json:"two_factor_enabled" example:"false"` // 是否启用二步验证
+}
+
+// Profile 返回不包含敏感信息的用户资料
+func (u *User) Profile() UserProfile {
+	return UserProfile{
+		ID:               u.ID,
+		Name:             u.Name,
+		Email:            u.Email,
+		Age:              u.Age,
+		Role:             u.Role,
+		IsActive:         u.IsActive,
+		EmailVerified:    u.EmailVerified,
+		TwoFactorEnabled: u.TwoFactorEnabled,
+	}
 }
 
 // UpdateUserRequest 更新用户请求
@@ -71,21 +116,63 @@ type UpdateUserRequest struct {
 	Age  int    `json:"age,omitempty" binding:"min=0" example:"30"` // 年龄（可选）
 }
 
-// CreateProductRequest 创建产品请求
+// CreateProductRequest 创建产品请求。提交变体时产品的价格区间和库存由变体汇总，price 和 stock 可以省略
 type CreateProductRequest struct {
-	Name        string  `json:"name" binding:"required" example:"iPhone 15"`     // 产品名称
-	Description string  `json:"description" example:"最新款智能手机"`                   // 产品描述
-	Price       float64 `json:"price" binding:"required,min=0" example:"999.99"` // 产品价格
-	Stock       int     `json:"stock" binding:"min=0" example:"100"`             // 库存数量
-	UserID      uint    `json:"user_id" binding:"required" example:"1"`          // 创建用户ID
+	Name        string                  `json:"name" binding:"required" example:"iPhone 15"` // 产品名称
+	Description string                  `json:"description" example:"最新款智能手机"`               // 产品描述
+	Price       *money.Money            `json:"price" binding:"required_without=Variants"`   // 产品价格，小数位数不能超过货币允许的位数
+	Stock       int                     `json:"stock" binding:"min=0" example:"100"`         // 库存数量
+	Variants    []ProductVariantRequest `json:"variants,omitempty" binding:"max=100,dive"`   // 产品变体（可选）
 }
 
 // UpdateProductRequest 更新产品请求
 type UpdateProductRequest struct {
-	Name        string  `json:"name,omitempty" example:"iPhone 15 Pro"`            // 产品名称（可选）
-	Description string  `json:"description,omitempty" example:"升级版智能手机"`           // 产品描述（可选）
-	Price       float64 `json:"price,omitempty" binding:"min=0" example:"1299.99"` // 产品价格（可选）
-	Stock       int     `json:"stock,omitempty" binding:"min=0" example:"50"`      // 库存数量（可选）
+	Name        string       `json:"name,omitempty" example:"iPhone 15 Pro"`                 // 产品名称（可选）
+	Description string       `json:"description,omitempty" example:"升级版智能手机"`                // 产品描述（可选）
+	Price       *money.Money `json:"price,omitempty"`                                        // 产品价格（可选），有变体时更换货币需同时提交变体
+	Stock       *int         `json:"stock,omitempty" binding:"omitempty,min=0" example:"50"` // 库存数量（可选），与当前库存的差额记为一次盘点调整
+	// 产品变体（可选）。提交时整体替换：带 id 的更新已有变体，不带 id 的新增，未列出的删除；
+	// 提交空数组删除全部变体，不提交则保持不变
+	Variants []ProductVariantRequest `json:"variants,omitempty" binding:"omitempty,max=100,dive"`
+}
+
+// Pagination 列表分页信息
+type Pagination struct {
+	Total      int64  `json:"total" example:"42"`                                                              // 符合过滤条件的总数
+	Page       int    `json:"page,omitempty" example:"2"`                                                      // 当前页码（从 1 开始，游标分页时不返回）
+	Limit      int    `json:"limit" example:"20"`                                                              // 每页数量
+	Offset     int    `json:"offset,omitempty" example:"20"`                                                   // 偏移量
+	Next       string `json:"next,omitempty" example:"/api/v1/products?page=3&page_size=20"`                   // 下一页链接
+	Prev       string `json:"prev,omitempty" example:"/api/v1/products?page=1&page_size=20"`                   // 上一页链接
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJzIjoiLWNyZWF0ZWRfYXQiLCJ2IjpbXX0"`             // 下一页游标（游标分页）
+	PrevCursor string `json:"prev_cursor,omitempty" example:"eyJzIjoiLWNyZWF0ZWRfYXQiLCJ2IjpbXSwiYiI6dHJ1ZX0"` // 上一页游标（游标分页）
+}
+
+// ProductListResponse 产品列表响应
+type ProductListResponse struct {
+	Data       []Product  `json:"data"`       // 当前页产品
+	Pagination Pagination `json:"pagination"` // 分页信息
+}
+
+// ProductSearchResult 产品搜索结果，高亮片段中匹配的词以 <mark></mark> 标记
+type ProductSearchResult struct {
+	Product
+	Rank                 float64 `json:"rank" example:"0.0759"`                                // 相关度
+	Match                string  `json:"match" example:"fulltext"`                             // 匹配方式：fulltext 全文检索，trigram 相似度
+	NameHighlight        string  `json:"name_highlight" example:"<mark>iPhone</mark> 15"`      // 高亮后的产品名称（HTML 转义后用 <mark> 标记匹配）
+	DescriptionHighlight string  `json:"description_highlight" example:"最新款<mark>智能手机</mark>"` // 产品描述中的匹配片段
+}
+
+// ProductSearchResponse 产品搜索响应
+type ProductSearchResponse struct {
+	Data       []ProductSearchResult `json:"data"`       // 当前页搜索结果，按相关度排序
+	Pagination Pagination            `json:"pagination"` // 分页信息
+}
+
+// UserListResponse 用户列表响应
+type UserListResponse struct {
+	Data       []User     `json:"data"`       // 当前页用户
+	Pagination Pagination `json:"pagination"` // 分页信息
 }
 
 // ErrorResponse 错误响应模型
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"go-webapi-example/money"
+	"time"
+)
+
+// 订单状态
+const (
+	OrderPending   = "pending"   // 待支付，库存已扣减，到达 expires_at 仍未支付时自动取消
+	OrderPaid      = "paid"      // 已支付
+	OrderFulfilled = "fulfilled" // 已发货
+	OrderCompleted = "completed" // 已完成
+	OrderCancelled = "cancelled" // 已取消，库存已退回
+	OrderRefunded  = "refunded"  // 已退款
+)
+
+// Order 订单，按组织隔离。行项目在下单时快照产品名称和单价，之后修改产品不影响订单
+type Order struct {
+	ID             uint                `gorm:"primarykey" json:"id" example:"1"`                       // 订单ID
+	CreatedAt      time.Time           `gorm:"index" json:"created_at"`                                // 下单时间
+	UpdatedAt      time.Time           `json:"updated_at"`                                             // 更新时间
+	OrganizationID uint                `gorm:"index;not null" json:"-"`                                // 所属组织ID
+	UserID         uint                `gorm:"index;not null" json:"user_id" example:"1"`              // 下单用户ID
+	Status         string              `gorm:"size:20;index;not null" json:"status" example:"pending"` // 状态：pending / paid / fulfilled / completed / cancelled / refunded
+	Total          money.Money         `gorm:"embedded;embeddedPrefix:total_" json:"total"`            // 订单总额
+	Note           string              `gorm:"size:500" json:"note,omitempty" example:"请尽快发货"`         // 买家备注
+	ExpiresAt      *time.Time          `gorm:"index" json:"expires_at,omitempty"`                      // 待支付订单的过期时间，离开 pending 状态后清空
+	Items          []OrderItem         `json:"items,omitempty"`                                        // 行项目
+	History        []OrderStatusChange `json:"history,omitempty"`                                      // 状态变更记录，按时间排序
+}
+
+// OrderItem 订单行项目
+type OrderItem struct {
+	ID             uint        `gorm:"primarykey" json:"id" example:"1"`                      // 行项目ID
+	CreatedAt      time.Time   `json:"created_at"`                                            // 创建时间
+	OrganizationID uint        `gorm:"index;not null" json:"-"`                               // 所属组织ID
+	OrderID        uint        `gorm:"index;not null" json:"order_id" example:"1"`            // 订单ID
+	ProductID      uint        `gorm:"index;not null" json:"product_id" example:"1"`          // 产品ID
+	VariantID      *uint       `gorm:"index" json:"variant_id,omitempty" example:"3"`         // 变体ID
+	ProductName    string      `gorm:"not null" json:"product_name" example:"iPhone 15"`      // 下单时的产品名称
+	SKU            string      `json:"sku,omitempty" example:"IP15-128-BLK"`                  // 下单时的变体 SKU
+	Quantity       int         `gorm:"not null" json:"quantity" example:"2"`                  // 数量
+	UnitPrice      money.Money `gorm:"embedded;embeddedPrefix:unit_price_" json:"unit_price"` // 下单时的单价
+	LineTotal      money.Money `gorm:"embedded;embeddedPrefix:line_total_" json:"line_total"` // 小计
+}
+
+// OrderStatusChange 订单状态变更记录，创建订单时记录一条 from_status 为空的记录
+type OrderStatusChange struct {
+	ID             uint      `gorm:"primarykey" json:"id" example:"1"`                       // 记录ID
+	CreatedAt      time.Time `json:"created_at"`                                             // 变更时间
+	OrganizationID uint      `gorm:"index;not null" json:"-"`                                // 所属组织ID
+	OrderID        uint      `gorm:"index;not null" json:"order_id" example:"1"`             // 订单ID
+	FromStatus     string    `gorm:"size:20" json:"from_status,omitempty" example:"pending"` // 变更前状态
+	ToStatus       string    `gorm:"size:20;not null" json:"to_status" example:"paid"`       // 变更后状态
+	ActorID        *uint     `json:"actor_id,omitempty" example:"1"`                         // 操作用户ID
+	Note           string    `gorm:"size:500" json:"note,omitempty" example:"线下付款已确认"`       // 备注
+}
+
+// OrderItemRequest 直接下单的行项目
+type OrderItemRequest struct {
+	ProductID uint  `json:"product_id" binding:"required" example:"1"`             // 产品ID
+	VariantID *uint `json:"variant_id,omitempty" example:"3"`                      // 变体ID，产品有变体时必填
+	Quantity  int   `json:"quantity" binding:"required,min=1,max=999" example:"2"` // 数量
+}
+
+// CreateOrderRequest 直接下单请求
+type CreateOrderRequest struct {
+	Items    []OrderItemRequest `json:"items" binding:"required,min=1,max=100,dive"`      // 行项目
+	Currency string             `json:"currency,omitempty" example:"USD"`                 // 订单货币（可选），默认 DEFAULT_CURRENCY
+	Note     string             `json:"note,omitempty" binding:"max=500" example:"请尽快发货"` // 买家备注
+}
+
+// CheckoutRequest 从购物车下单请求
+type CheckoutRequest struct {
+	Note string `json:"note,omitempty" binding:"max=500" example:"请尽快发货"` // 买家备注
+}
+
+// OrderTransitionRequest 推进订单状态请求
+type OrderTransitionRequest struct {
+	Status  string `json:"status" binding:"required,oneof=paid fulfilled completed cancelled refunded" example:"paid"` // 目标状态
+	Note    string `json:"note,omitempty" binding:"max=500" example:"线下付款已确认"`                                         // 备注
+	Restock bool   `json:"restock,omitempty" example:"false"`                                                          // 发货后退款时是否退回库存（取消和发货前退款总是退回）
+}
+
+// OrderListResponse 订单列表响应
+type OrderListResponse struct {
+	Data       []Order    `json:"data"`       // 当前页订单
+	Pagination Pagination `json:"pagination"` // 分页信息
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// DefaultOrganizationSlug 迁移时创建的默认组织，升级前的数据和公开注册的用户归入该组织
+const DefaultOrganizationSlug = "default"
+
+// 组织内角色：owner 和 admin 可以管理组织成员
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
+
+// Organization 组织（租户），不同组织之间的用户和产品相互隔离
+type Organization struct {
+	ID        uint      `gorm:"primarykey" json:"id" example:"1"`                // 组织ID
+	CreatedAt time.Time `json:"created_at"`                                      // 创建时间
+	UpdatedAt time.Time `json:"updated_at"`                                      // 更新时间
+	Name      string    `gorm:"not null" json:"name" example:"华东事业部"`            // 组织名称
+	Slug      string    `gorm:"uniqueIndex;not null" json:"slug" example:"east"` // 组织标识
+
+	PublicStorefront bool `gorm:"not null;default:false" json:"public_storefront" example:"false"` // 是否开放匿名店面：允许未登录访客使用匿名购物车，查看产品名称、价格和可售库存
+}
+
+// Membership 用户在组织中的成员关系及组织内角色
+type Membership struct {
+	ID             uint          `gorm:"primarykey" json:"id" example:"1"`                                                // 成员关系ID
+	CreatedAt      time.Time     `json:"created_at"`                                                                      // 加入时间
+	UpdatedAt      time.Time     `json:"updated_at"`                                                                      // 更新时间
+	OrganizationID uint          `gorm:"uniqueIndex:idx_membership_org_user;not null" json:"organization_id" example:"1"` // 组织ID
+	UserID         uint          `gorm:"uniqueIndex:idx_membership_org_user;index;not null" json:"user_id" example:"1"`   // 用户ID
+	Role           string        `gorm:"not null;default:'member'" json:"role" example:"member"`                          // 组织内角色（owner/admin/member）
+	User           *UserProfile  `gorm:"-" json:"user,omitempty"`                                                         // 成员资料
+	Organization   *Organization `json:"organization,omitempty"`                                                          // 组织信息
+}
+
+// CreateOrganizationRequest 创建组织请求
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"华东事业部"`                  // 组织名称
+	Slug string `json:"slug" binding:"required,max=50,alphanum,lowercase" example:"east"` // 组织标识（小写字母和数字）
+}
+
+// UpdateOrganizationSettingsRequest 修改组织设置请求
+type UpdateOrganizationSettingsRequest struct {
+	PublicStorefront *bool `json:"public_storefront" binding:"required" example:"true"` // 是否开放匿名店面
+}
+
+// AddMemberRequest 添加组织成员请求
+type AddMemberRequest struct {
+	UserID uint   `json:"user_id" binding:"required" example:"2"`                            // 用户ID
+	Role   string `json:"role" binding:"required,oneof=owner admin member" example:"member"` // 组织内角色
+}
+
+// UpdateMemberRequest 修改组织成员角色请求
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner admin member" example:"admin"` // 组织内角色
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"go-webapi-example/money"
+	"time"
+)
+
+// 报价来源
+const (
+	PriceSourceBase      = "base"      // 产品自身的价格
+	PriceSourceList      = "list"      // 价格表中该货币的价格
+	PriceSourceConverted = "converted" // 按本地汇率表从产品价格换算
+)
+
+// ProductPrice 产品在某种货币下单独设定的价格（价格表），按组织隔离。
+// 每个产品每种货币最多一条，产品自身的货币不需要也不允许单独设定
+type ProductPrice struct {
+	ID             uint        `gorm:"primarykey" json:"id" example:"1"`             // 价格ID
+	CreatedAt      time.Time   `json:"created_at"`                                   // 创建时间
+	UpdatedAt      time.Time   `json:"updated_at"`                                   // 更新时间
+	OrganizationID uint        `gorm:"index;not null" json:"-"`                      // 所属组织ID
+	ProductID      uint        `gorm:"index;not null" json:"product_id" example:"1"` // 产品ID
+	Price          money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`  // 该货币下的价格
+}
+
+// SetProductPricesRequest 整体替换产品价格表的请求
+type SetProductPricesRequest struct {
+	Prices []money.Money `json:"prices" binding:"max=50"` // 各货币的价格，每种货币最多一条，提交空数组清空价格表
+}
+
+// PriceQuote 产品在指定货币下的报价
+type PriceQuote struct {
+	ProductID uint        `json:"product_id" example:"1"`              // 产品ID
+	Price     money.Money `json:"price"`                               // 报价
+	Source    string      `json:"source" example:"converted"`          // 来源：base / list / converted
+	BasePrice money.Money `json:"base_price"`                          // 产品自身的价格
+	Rate      string      `json:"rate,omitempty" example:"0.92000000"` // 换算使用的汇率（source 为 converted 时返回）
+}
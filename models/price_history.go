@@ -0,0 +1,67 @@
+package models
+
+import (
+	"go-webapi-example/money"
+	"time"
+)
+
+// 价格变动来源
+const (
+	PriceChangeCreated       = "created"        // 创建产品或变体时的初始价格
+	PriceChangeManual        = "manual"         // 修改产品或变体
+	PriceChangeScheduled     = "scheduled"      // 定时价格开始生效
+	PriceChangeScheduleEnded = "schedule_ended" // 定时价格结束，恢复原价格
+)
+
+// 定时价格状态
+const (
+	ScheduledPricePending   = "scheduled" // 等待生效
+	ScheduledPriceActive    = "active"    // 生效中，结束时恢复原价格
+	ScheduledPriceCompleted = "completed" // 已结束（没有结束时间的定时价格生效后即为已结束）
+	ScheduledPriceCancelled = "cancelled" // 已取消
+)
+
+// PriceChange 价格变动记录，按组织隔离。产品价格和变体价格的每次变化都记录一条，变体的记录带有 variant_id
+type PriceChange struct {
+	ID               uint        `gorm:"primarykey" json:"id" example:"1"`                    // 记录ID
+	CreatedAt        time.Time   `gorm:"index" json:"created_at"`                             // 变动时间
+	OrganizationID   uint        `gorm:"index;not null" json:"-"`                             // 所属组织ID
+	ProductID        uint        `gorm:"index;not null" json:"product_id" example:"1"`        // 产品ID
+	VariantID        *uint       `gorm:"index" json:"variant_id,omitempty" example:"3"`       // 变体ID，产品价格变动时为空
+	OldPrice         money.Money `gorm:"embedded;embeddedPrefix:old_price_" json:"old_price"` // 变动前价格，初始价格的记录为空
+	NewPrice         money.Money `gorm:"embedded;embeddedPrefix:new_price_" json:"new_price"` // 变动后价格
+	Source           string      `gorm:"size:20;not null" json:"source" example:"manual"`     // 来源：created / manual / scheduled / schedule_ended
+	ScheduledPriceID *uint       `json:"scheduled_price_id,omitempty" example:"2"`            // 触发变动的定时价格ID
+	ActorID          *uint       `gorm:"index" json:"actor_id,omitempty" example:"1"`         // 操作用户ID，定时价格为创建定时价格的用户
+}
+
+// ScheduledPrice 定时价格：在 starts_at 把产品价格改为指定价格，到达 ends_at 时恢复生效前的价格，
+// 由后台任务按时应用。同一产品等待生效和生效中的定时价格时间窗口不能重叠
+type ScheduledPrice struct {
+	ID             uint        `gorm:"primarykey" json:"id" example:"1"`                              // 定时价格ID
+	CreatedAt      time.Time   `json:"created_at"`                                                    // 创建时间
+	UpdatedAt      time.Time   `json:"updated_at"`                                                    // 更新时间
+	OrganizationID uint        `gorm:"index;not null" json:"-"`                                       // 所属组织ID
+	ProductID      uint        `gorm:"index;not null" json:"product_id" example:"1"`                  // 产品ID
+	Price          money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`                   // 生效期间的价格
+	PreviousPrice  money.Money `gorm:"embedded;embeddedPrefix:previous_price_" json:"previous_price"` // 生效前的价格，结束时恢复（生效后记录）
+	StartsAt       time.Time   `gorm:"index;not null" json:"starts_at"`                               // 开始时间
+	EndsAt         *time.Time  `gorm:"index" json:"ends_at,omitempty"`                                // 结束时间，为空表示长期生效
+	Status         string      `gorm:"size:20;index;not null" json:"status" example:"scheduled"`      // 状态：scheduled / active / completed / cancelled
+	AppliedAt      *time.Time  `json:"applied_at,omitempty"`                                          // 实际生效时间
+	EndedAt        *time.Time  `json:"ended_at,omitempty"`                                            // 实际结束或取消时间
+	ActorID        *uint       `json:"actor_id,omitempty" example:"1"`                                // 创建用户ID
+}
+
+// SchedulePriceRequest 创建定时价格请求
+type SchedulePriceRequest struct {
+	Price    money.Money `json:"price"`                                                       // 生效期间的价格，货币需与产品相同
+	StartsAt time.Time   `json:"starts_at" binding:"required" example:"2026-11-11T00:00:00Z"` // 开始时间
+	EndsAt   *time.Time  `json:"ends_at,omitempty" example:"2026-11-12T00:00:00Z"`            // 结束时间（可选），必须晚于开始时间
+}
+
+// PriceChangeListResponse 价格变动列表响应
+type PriceChangeListResponse struct {
+	Data       []PriceChange `json:"data"`       // 当前页记录
+	Pagination Pagination    `json:"pagination"` // 分页信息
+}
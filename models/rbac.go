@@ -0,0 +1,102 @@
+package models
+
+import "time"
+
+// 内置角色
+const (
+	RoleUser       = "user"
+	RoleAdmin      = "admin"
+	RoleSuperAdmin = "superadmin"
+)
+
+// 权限标识，格式为 "资源:操作"
+const (
+	PermUsersRead        = "users:read"           // 查看任意用户
+	PermUsersWrite       = "users:write"          // 创建和修改任意用户
+	PermUsersDelete      = "users:delete"         // 删除用户
+	PermUsersManage      = "users:manage"         // 账户安全管理（邮箱验证、解锁、锁定事件）
+	PermUsersActivate    = "users:activate"       // 激活和停用账户
+	PermProductsRead     = "products:read"        // 查看产品
+	PermProductsWrite    = "products:write"       // 创建和修改产品
+	PermProductsDelete   = "products:delete"      // 删除产品
+	PermProductsManage   = "products:manage"      // 修改和删除任意用户的产品
+	PermCategoriesManage = "categories:manage"    // 管理产品分类
+	PermRolesManage      = "roles:manage"         // 管理角色并为用户分配角色
+	PermOrgsManage       = "organizations:manage" // 创建组织并访问任意组织
+	PermOrdersManage     = "orders:manage"        // 查看全部订单并推进订单状态
+	PermOrdersWrite      = "orders:write"         // 预留库存、下单和取消自己的订单
+)
+
+// PermissionCatalog 系统支持的全部权限及说明，迁移时写入 permissions 表
+var PermissionCatalog = []Permission{
+	{Name: PermUsersRead, Description: "查看任意用户"},
+	{Name: PermUsersWrite, Description: "创建和修改任意用户"},
+	{Name: PermUsersDelete, Description: "删除用户"},
+	{Name: PermUsersManage, Description: "账户安全管理（邮箱验证、解锁、锁定事件）"},
+	{Name: PermUsersActivate, Description: "激活和停用账户"},
+	{Name: PermProductsRead, Description: "查看产品"},
+	{Name: PermProductsWrite, Description: "创建和修改产品"},
+	{Name: PermProductsDelete, Description: "删除产品"},
+	{Name: PermProductsManage, Description: "修改和删除任意用户的产品"},
+	{Name: PermCategoriesManage, Description: "管理产品分类"},
+	{Name: PermRolesManage, Description: "管理角色并为用户分配角色"},
+	{Name: PermOrgsManage, Description: "创建组织并访问任意组织"},
+	{Name: PermOrdersManage, Description: "查看全部订单并推进订单状态"},
+	{Name: PermOrdersWrite, Description: "预留库存、下单和取消自己的订单"},
+}
+
+// DefaultRolePermissions 内置角色的默认权限，新增到目录中的权限在迁移时也会授予对应的内置角色；superadmin 始终拥有全部权限
+var DefaultRolePermissions = map[string][]string{
+	RoleUser: {PermProductsRead, PermProductsWrite, PermProductsDelete, PermOrdersWrite},
+	RoleAdmin: {
+		PermUsersRead, PermUsersWrite, PermUsersDelete, PermUsersManage,
+		PermProductsRead, PermProductsWrite, PermProductsDelete, PermProductsManage,
+		PermCategoriesManage, PermOrdersManage, PermOrdersWrite,
+	},
+}
+
+// Permission 权限
+type Permission struct {
+	ID          uint   `gorm:"primarykey" json:"id" example:"1"`                          // 权限ID
+	Name        string `gorm:"uniqueIndex;not null" json:"name" example:"products:write"` // 权限标识
+	Description string `json:"description" example:"创建和修改产品"`                             // 权限说明
+}
+
+// Role 角色，用户通过 User.Role 引用角色名称
+type Role struct {
+	ID          uint         `gorm:"primarykey" json:"id" example:"1"`                     // 角色ID
+	CreatedAt   time.Time    `json:"created_at"`                                           // 创建时间
+	UpdatedAt   time.Time    `json:"updated_at"`                                           // 更新时间
+	Name        string       `gorm:"uniqueIndex;not null" json:"name" example:"editor"`    // 角色名称
+	Description string       `json:"description" example:"内容编辑"`                           // 角色说明
+	System      bool         `gorm:"not null;default:false" json:"system" example:"false"` // 是否为内置角色（不可删除）
+	Permissions []Permission `gorm:"many2many:role_permissions;" json:"permissions"`       // 角色拥有的权限
+}
+
+// CreateRoleRequest 创建角色请求
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required,max=50" example:"editor"`                       // 角色名称
+	Description string   `json:"description" example:"内容编辑"`                                            // 角色说明
+	Permissions []string `json:"permissions" binding:"required" example:"products:read,products:write"` // 权限标识列表
+}
+
+// UpdateRoleRequest 更新角色请求，权限列表整体替换
+type UpdateRoleRequest struct {
+	Description string   `json:"description" example:"内容编辑"`                                            // 角色说明
+	Permissions []string `json:"permissions" binding:"required" example:"products:read,products:write"` // 权限标识列表
+}
+
+// AssignRoleRequest 为用户分配角色请求
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required" example:"admin"` // 角色名称
+}
+
+// UpdateUserStatusRequest 激活或停用用户请求
+type UpdateUserStatusRequest struct {
+	IsActive *bool `json:"is_active" binding:"required" example:"false"` // 是否激活
+}
+
+// TransferSuperAdminRequest 移交超级管理员请求
+type TransferSuperAdminRequest struct {
+	UserID uint `json:"user_id" binding:"required" example:"2"` // 接收 superadmin 角色的用户ID
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// 库存预留状态
+const (
+	ReservationActive    = "active"    // 预留中，占用可售库存
+	ReservationConfirmed = "confirmed" // 已确认，库存已扣减
+	ReservationReleased  = "released"  // 已释放
+	ReservationExpired   = "expired"   // 超时未确认，已自动释放
+)
+
+// StockReservation 结账期间为用户预留的库存，按组织隔离。
+// 预留中且未过期的数量从可售库存中扣除，确认时扣减实际库存，超时后由后台任务释放
+type StockReservation struct {
+	ID             uint       `gorm:"primarykey" json:"id" example:"1"`                      // 预留ID
+	CreatedAt      time.Time  `json:"created_at"`                                            // 创建时间
+	UpdatedAt      time.Time  `json:"updated_at"`                                            // 更新时间
+	OrganizationID uint       `gorm:"index;not null" json:"-"`                               // 所属组织ID
+	ProductID      uint       `gorm:"index;not null" json:"product_id" example:"1"`          // 产品ID
+	VariantID      *uint      `gorm:"index" json:"variant_id,omitempty" example:"3"`         // 变体ID，产品没有变体时为空
+	UserID         uint       `gorm:"index;not null" json:"user_id" example:"1"`             // 预留用户ID
+	Quantity       int        `gorm:"not null" json:"quantity" example:"2"`                  // 预留数量
+	Status         string     `gorm:"size:20;index;not null" json:"status" example:"active"` // 状态：active / confirmed / released / expired
+	ExpiresAt      time.Time  `gorm:"index;not null" json:"expires_at"`                      // 过期时间
+	ConfirmedAt    *time.Time `json:"confirmed_at,omitempty"`                                // 确认时间
+	ReleasedAt     *time.Time `json:"released_at,omitempty"`                                 // 释放或过期时间
+}
+
+// ReserveStockRequest 预留库存请求
+type ReserveStockRequest struct {
+	Quantity   int   `json:"quantity" binding:"required,min=1" example:"2"`       // 预留数量
+	VariantID  *uint `json:"variant_id,omitempty" example:"3"`                    // 变体ID，产品有变体时必填
+	TTLSeconds int   `json:"ttl_seconds,omitempty" binding:"min=0" example:"900"` // 预留时长（秒），默认 RESERVATION_TTL，不超过 RESERVATION_MAX_TTL
+}
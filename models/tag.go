@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Tag 产品标签，按组织隔离。名称统一保存为小写
+type Tag struct {
+	ID             uint      `gorm:"primarykey" json:"id" example:"1"`                               // 标签ID
+	CreatedAt      time.Time `json:"created_at"`                                                     // 创建时间
+	OrganizationID uint      `gorm:"uniqueIndex:idx_tag_org_name;not null" json:"-"`                 // 所属组织ID
+	Name           string    `gorm:"uniqueIndex:idx_tag_org_name;not null" json:"name" example:"5g"` // 标签名称
+}
+
+// AddProductTagsRequest 为产品添加标签请求，不存在的标签会自动创建
+type AddProductTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1,max=20,dive,required,max=50" example:"5g,新品"` // 标签名称列表
+}
+
+// TagFacet 标签分面计数
+type TagFacet struct {
+	Name  string `json:"name" example:"5g"`  // 标签名称
+	Count int64  `json:"count" example:"12"` // 符合过滤条件且带有该标签的产品数量
+}
+
+// PriceBucket 价格区间分面计数，区间为 [min, max)
+type PriceBucket struct {
+	Min   *float64 `json:"min" example:"100"` // 区间下限，为空表示不限
+	Max   *float64 `json:"max" example:"500"` // 区间上限（不含），为空表示不限
+	Count int64    `json:"count" example:"8"` // 价格在区间内的产品数量
+}
+
+// ProductFacetsResponse 产品分面统计响应
+type ProductFacetsResponse struct {
+	Total        int64         `json:"total" example:"42"`      // 符合过滤条件的产品总数
+	Tags         []TagFacet    `json:"tags"`                    // 标签计数，按数量倒序
+	PriceBuckets []PriceBucket `json:"price_buckets,omitempty"` // 价格区间计数，只在指定 currency 时统计
+}
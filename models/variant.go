@@ -0,0 +1,30 @@
+package models
+
+import (
+	"go-webapi-example/money"
+	"time"
+)
+
+// ProductVariant 产品变体（规格），例如不同尺码、颜色。SKU 在组织内唯一，价格和库存独立于其他变体
+type ProductVariant struct {
+	ID             uint              `gorm:"primarykey" json:"id" example:"1"`                                           // 变体ID
+	CreatedAt      time.Time         `json:"created_at"`                                                                 // 创建时间
+	UpdatedAt      time.Time         `json:"updated_at"`                                                                 // 更新时间
+	OrganizationID uint              `gorm:"uniqueIndex:idx_variant_org_sku;not null" json:"-"`                          // 所属组织ID
+	ProductID      uint              `gorm:"index;not null" json:"product_id" example:"1"`                               // 产品ID
+	SKU            string            `gorm:"uniqueIndex:idx_variant_org_sku;not null" json:"sku" example:"IP15-128-BLK"` // 库存单位编码
+	Options        map[string]string `gorm:"serializer:json;type:jsonb;not null" json:"options"`                         // 规格属性，例如 {"color": "黑色", "storage": "128GB"}
+	Price          money.Money       `gorm:"embedded;embeddedPrefix:price_" json:"price"`                                // 变体价格，货币与产品相同
+	Stock          int               `gorm:"not null;default:0" json:"stock" example:"30"`                               // 变体库存
+	Available      int               `gorm:"-" json:"available" example:"28"`                                            // 可售库存：库存减去预留中的数量
+	Position       int               `gorm:"not null;default:0" json:"position" example:"0"`                             // 排列顺序
+}
+
+// ProductVariantRequest 创建或更新产品时嵌套提交的变体。更新产品时 id 为已有变体的ID，为空表示新增
+type ProductVariantRequest struct {
+	ID      uint              `json:"id,omitempty" example:"1"`                                                    // 已有变体ID（更新时可选）
+	SKU     string            `json:"sku" binding:"required,max=64" example:"IP15-128-BLK"`                        // 库存单位编码
+	Options map[string]string `json:"options" binding:"max=10,dive,keys,required,max=50,endkeys,required,max=100"` // 规格属性
+	Price   *money.Money      `json:"price" binding:"required"`                                                    // 变体价格，必须大于 0，货币需与产品相同
+	Stock   *int              `json:"stock,omitempty" binding:"omitempty,min=0" example:"30"`                      // 变体库存：新增变体的初始库存；已有变体不提交时保持不变，不能低于预留中的数量
+}
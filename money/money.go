@@ -0,0 +1,221 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown ISO 4217 currency code")
+	ErrInvalidAmount    = errors.New("amount must be a decimal number")
+	ErrTooManyDecimals  = errors.New("amount has more decimal places than the currency allows")
+	ErrAmountOutOfRange = errors.New("amount is out of range")
+	ErrCurrencyMismatch = errors.New("amounts have different currencies")
+)
+
+// scales ISO 4217 货币代码及其小数位数（最小货币单位与主单位的比例为 10^scale）
+var scales = map[string]int{
+	"AED": 2, "AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0, "CNY": 2,
+	"CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2, "IDR": 2, "ILS": 2,
+	"INR": 2, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "MYR": 2,
+	"NOK": 2, "NZD": 2, "OMR": 3, "PHP": 2, "PLN": 2, "RUB": 2, "SAR": 2, "SEK": 2,
+	"SGD": 2, "THB": 2, "TND": 3, "TRY": 2, "TWD": 2, "USD": 2, "VND": 0, "ZAR": 2,
+}
+
+// Scale 货币的小数位数
+func Scale(currency string) (int, error) {
+	scale, ok := scales[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+	return scale, nil
+}
+
+// ScaleSQL 根据货币代码列计算小数位数的 SQL 表达式，用于在数据库中把最小货币单位换算为主单位
+func ScaleSQL(column string) string {
+	codes := make([]string, 0, len(scales))
+	for code := range scales {
+		if scales[code] != 2 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	var b strings.Builder
+	b.WriteString("CASE " + column)
+	for _, code := range codes {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", code, scales[code])
+	}
+	b.WriteString(" ELSE 2 END")
+	return b.String()
+}
+
+// Valid 是否为支持的 ISO 4217 货币代码
+func Valid(currency string) bool {
+	_, ok := scales[currency]
+	return ok
+}
+
+// Money 金额，以最小货币单位（例如美分）的整数保存，避免浮点数的舍入误差。
+// 嵌入模型时保存为 <prefix>amount 和 <prefix>currency 两列；
+// JSON 格式为 {"amount": "999.99", "currency": "USD"}，金额使用字符串以保证精确
+type Money struct {
+	Amount   int64  `gorm:"not null;default:0" json:"amount" swaggertype:"string" example:"999.99"` // 金额：数据库中为最小货币单位的整数，JSON 中为主单位的十进制字符串
+	Currency string `gorm:"size:3;not null;default:''" json:"currency" example:"USD"`               // ISO 4217 货币代码
+}
+
+// New 以最小货币单位创建金额
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Parse 解析十进制金额字符串，小数位数不能超过货币允许的位数，例如 USD 接受 "12.5" 和 "12.50"，不接受 "12.505"
+func Parse(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	scale, err := Scale(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" || !digits(whole) || !digits(frac) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > scale {
+		return Money{}, fmt.Errorf("%w: %s allows %d", ErrTooManyDecimals, currency, scale)
+	}
+
+	var n int64
+	if minor := strings.TrimLeft(whole+frac+strings.Repeat("0", scale-len(frac)), "0"); minor != "" {
+		if n, err = strconv.ParseInt(minor, 10, 64); err != nil {
+			return Money{}, ErrAmountOutOfRange
+		}
+	}
+	if negative {
+		n = -n
+	}
+	return Money{Amount: n, Currency: currency}, nil
+}
+
+// String 十进制表示，例如 "999.99"
+func (m Money) String() string {
+	scale, err := Scale(m.Currency)
+	if err != nil || scale == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+	sign := ""
+	abs := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign, abs = "-", uint64(-m.Amount)
+	}
+	s := strconv.FormatUint(abs, 10)
+	if len(s) <= scale {
+		s = strings.Repeat("0", scale-len(s)+1) + s
+	}
+	return sign + s[:len(s)-scale] + "." + s[len(s)-scale:]
+}
+
+// Float 主单位的近似值，只用于统计分组等不要求精确的场景
+func (m Money) Float() float64 {
+	scale, _ := Scale(m.Currency)
+	return float64(m.Amount) / math.Pow10(scale)
+}
+
+// IsZero 金额和货币都为空
+func (m Money) IsZero() bool {
+	return m.Amount == 0 && m.Currency == ""
+}
+
+// Add 相同货币的金额相加
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	sum := m.Amount + o.Amount
+	if (o.Amount > 0 && sum < m.Amount) || (o.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrAmountOutOfRange
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Mul 金额乘以数量。MinInt64 * -1 溢出后仍等于 MinInt64，除法检查发现不了，单独判断
+func (m Money) Mul(n int64) (Money, error) {
+	if n != 0 && ((m.Amount*n)/n != m.Amount || (m.Amount == math.MinInt64 && n == -1)) {
+		return Money{}, ErrAmountOutOfRange
+	}
+	return Money{Amount: m.Amount * n, Currency: m.Currency}, nil
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.String(), m.Currency})
+}
+
+// UnmarshalJSON amount 接受字符串或数字，数字按原始文本解析，不经过浮点数
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Amount   json.RawMessage `json:"amount"`
+		Currency string          `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	parsed, err := Parse(strings.Trim(string(raw.Amount), `"`), raw.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// convert 按汇率换算金额，结果按最小货币单位四舍五入（远离零）
+func convert(m Money, to string, rate *big.Rat) (Money, error) {
+	fromScale, err := Scale(m.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	toScale, err := Scale(to)
+	if err != nil {
+		return Money{}, err
+	}
+
+	v := new(big.Rat).SetInt64(m.Amount)
+	v.Mul(v, rate)
+	v.Mul(v, new(big.Rat).SetFrac(pow10(toScale), pow10(fromScale)))
+
+	// 四舍五入：(2n + d) / 2d 向零取整，负数对称处理
+	num, den := new(big.Int).Abs(v.Num()), v.Denom()
+	q := new(big.Int).Div(new(big.Int).Add(new(big.Int).Mul(num, big.NewInt(2)), den), new(big.Int).Mul(den, big.NewInt(2)))
+	if v.Sign() < 0 {
+		q.Neg(q)
+	}
+	if !q.IsInt64() {
+		return Money{}, ErrAmountOutOfRange
+	}
+	return Money{Amount: q.Int64(), Currency: to}, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     Money
+		err      error
+	}{
+		{"999.99", "USD", New(99999, "USD"), nil},
+		{"12.5", "usd", New(1250, "USD"), nil},
+		{"12.50", "USD", New(1250, "USD"), nil},
+		{"12.500", "USD", New(1250, "USD"), nil},
+		{"12", "USD", New(1200, "USD"), nil},
+		{"12.", "USD", New(1200, "USD"), nil},
+		{".5", "USD", New(50, "USD"), nil},
+		{"0", "USD", New(0, "USD"), nil},
+		{"-0.01", "USD", New(-1, "USD"), nil},
+		{"+3", "USD", New(300, "USD"), nil},
+		{" 7.25 ", "EUR", New(725, "EUR"), nil},
+		{"100", "JPY", New(100, "JPY"), nil},
+		{"1.234", "KWD", New(1234, "KWD"), nil},
+		{"92233720368547758.07", "USD", New(math.MaxInt64, "USD"), nil},
+		{"12.505", "USD", Money{}, ErrTooManyDecimals},
+		{"100.5", "JPY", Money{}, ErrTooManyDecimals},
+		{"92233720368547758.08", "USD", Money{}, ErrAmountOutOfRange},
+		{"", "USD", Money{}, ErrInvalidAmount},
+		{".", "USD", Money{}, ErrInvalidAmount},
+		{"1e3", "USD", Money{}, ErrInvalidAmount},
+		{"1,000", "USD", Money{}, ErrInvalidAmount},
+		{"--1", "USD", Money{}, ErrInvalidAmount},
+		{"1.2.3", "USD", Money{}, ErrInvalidAmount},
+		{"1", "XXX", Money{}, ErrUnknownCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			got, err := Parse(tt.amount, tt.currency)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Parse(%q, %q) error = %v, want %v", tt.amount, tt.currency, err, tt.err)
+			}
+			if got != tt.want {
+				t.Fatalf("Parse(%q, %q) = %+v, want %+v", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{New(99999, "USD"), "999.99"},
+		{New(1250, "USD"), "12.50"},
+		{New(5, "USD"), "0.05"},
+		{New(0, "USD"), "0.00"},
+		{New(-1, "USD"), "-0.01"},
+		{New(-12345, "EUR"), "-123.45"},
+		{New(100, "JPY"), "100"},
+		{New(1234, "KWD"), "1.234"},
+		{New(7, "KWD"), "0.007"},
+		{New(math.MaxInt64, "USD"), "92233720368547758.07"},
+		{New(math.MinInt64, "USD"), "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestParseStringRoundTrip(t *testing.T) {
+	for _, m := range []Money{New(99999, "USD"), New(-1, "EUR"), New(100, "JPY"), New(1, "KWD"), New(math.MaxInt64, "USD")} {
+		got, err := Parse(m.String(), m.Currency)
+		if err != nil || got != m {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", m.String(), got, err, m)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Money
+		want Money
+		err  error
+	}{
+		{"sum", New(150, "USD"), New(250, "USD"), New(400, "USD"), nil},
+		{"negative", New(150, "USD"), New(-250, "USD"), New(-100, "USD"), nil},
+		{"max", New(math.MaxInt64-1, "USD"), New(1, "USD"), New(math.MaxInt64, "USD"), nil},
+		{"overflow", New(math.MaxInt64, "USD"), New(1, "USD"), Money{}, ErrAmountOutOfRange},
+		{"underflow", New(math.MinInt64, "USD"), New(-1, "USD"), Money{}, ErrAmountOutOfRange},
+		{"currency mismatch", New(1, "USD"), New(1, "EUR"), Money{}, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Add(tt.b)
+			if !errors.Is(err, tt.err) || got != tt.want {
+				t.Fatalf("Add = %+v, %v, want %+v, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Money
+		n    int64
+		want Money
+		err  error
+	}{
+		{"quantity", New(1999, "USD"), 3, New(5997, "USD"), nil},
+		{"zero", New(math.MaxInt64, "USD"), 0, New(0, "USD"), nil},
+		{"negative", New(1999, "USD"), -2, New(-3998, "USD"), nil},
+		{"overflow", New(math.MaxInt64/2+1, "USD"), 2, Money{}, ErrAmountOutOfRange},
+		{"large quantity", New(1<<40, "USD"), 1 << 30, Money{}, ErrAmountOutOfRange},
+		{"min times minus one", New(math.MinInt64, "USD"), -1, Money{}, ErrAmountOutOfRange},
+		{"minus one times min", New(-1, "USD"), math.MinInt64, Money{}, ErrAmountOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Mul(tt.n)
+			if !errors.Is(err, tt.err) || got != tt.want {
+				t.Fatalf("Mul = %+v, %v, want %+v, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(New(99999, "USD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"amount":"999.99","currency":"USD"}` {
+		t.Fatalf("Marshal = %s", data)
+	}
+
+	for _, raw := range []string{`{"amount":"999.99","currency":"USD"}`, `{"amount":999.99,"currency":"usd"}`} {
+		var m Money
+		if err := json.Unmarshal([]byte(raw), &m); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", raw, err)
+		}
+		if m != New(99999, "USD") {
+			t.Fatalf("Unmarshal(%s) = %+v", raw, m)
+		}
+	}
+
+	var m Money
+	if err := json.Unmarshal([]byte(`{"amount":0.001,"currency":"USD"}`), &m); !errors.Is(err, ErrTooManyDecimals) {
+		t.Fatalf("Unmarshal with extra decimals: got %v", err)
+	}
+}
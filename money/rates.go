@@ -0,0 +1,68 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrNoRate 汇率表中没有该货币
+var ErrNoRate = errors.New("no exchange rate configured for currency")
+
+// Rates 本地配置的汇率表，每种货币的汇率为 1 单位基准货币可兑换的数量，汇率使用精确的有理数
+type Rates struct {
+	Base  string
+	rates map[string]*big.Rat
+}
+
+// ParseRates 解析 "EUR=0.92" 形式的汇率列表，基准货币自身的汇率固定为 1
+func ParseRates(base string, entries []string) (*Rates, error) {
+	base = strings.ToUpper(base)
+	if !Valid(base) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCurrency, base)
+	}
+	r := &Rates{Base: base, rates: map[string]*big.Rat{base: big.NewRat(1, 1)}}
+	for _, entry := range entries {
+		code, value, ok := strings.Cut(entry, "=")
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !ok || !Valid(code) {
+			return nil, fmt.Errorf("invalid exchange rate %q", entry)
+		}
+		rate, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate %q", entry)
+		}
+		r.rates[code] = rate
+	}
+	return r, nil
+}
+
+// Rate from 到 to 的汇率
+func (r *Rates) Rate(from, to string) (*big.Rat, error) {
+	fromRate, ok := r.rates[from]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoRate, from)
+	}
+	toRate, ok := r.rates[to]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoRate, to)
+	}
+	return new(big.Rat).Quo(toRate, fromRate), nil
+}
+
+// Convert 把金额换算为 to 货币，同时返回使用的汇率
+func (r *Rates) Convert(m Money, to string) (Money, *big.Rat, error) {
+	if m.Currency == to {
+		return m, big.NewRat(1, 1), nil
+	}
+	rate, err := r.Rate(m.Currency, to)
+	if err != nil {
+		return Money{}, nil, err
+	}
+	converted, err := convert(m, to, rate)
+	if err != nil {
+		return Money{}, nil, err
+	}
+	return converted, rate, nil
+}
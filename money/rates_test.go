@@ -0,0 +1,87 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseRates(t *testing.T) {
+	rates, err := ParseRates("usd", []string{"EUR=0.92", " jpy = 151.3 "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates.Base != "USD" {
+		t.Fatalf("Base = %q, want USD", rates.Base)
+	}
+	rate, err := rates.Rate("EUR", "JPY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.RatString() != "7565/46" { // 151.3 / 0.92
+		t.Fatalf("EUR->JPY rate = %s", rate.RatString())
+	}
+
+	for _, entries := range [][]string{{"EUR"}, {"XXX=1"}, {"EUR=abc"}, {"EUR=0"}, {"EUR=-1"}} {
+		if _, err := ParseRates("USD", entries); err == nil {
+			t.Errorf("ParseRates(%q) succeeded, want error", entries)
+		}
+	}
+	if _, err := ParseRates("XXX", nil); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("ParseRates with unknown base: got %v", err)
+	}
+}
+
+// TestConvertRounding 换算结果按最小货币单位四舍五入，恰好一半时远离零（不是银行家舍入）
+func TestConvertRounding(t *testing.T) {
+	rates, err := ParseRates("USD", []string{"EUR=0.5", "JPY=151.3", "KWD=0.307"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		from Money
+		to   string
+		want Money
+	}{
+		{"exact", New(200, "USD"), "EUR", New(100, "EUR")},
+		{"half rounds up", New(1, "USD"), "EUR", New(1, "EUR")},
+		{"one and a half", New(3, "USD"), "EUR", New(2, "EUR")},
+		{"two and a half rounds away from zero", New(5, "USD"), "EUR", New(3, "EUR")},
+		{"negative half rounds away from zero", New(-5, "USD"), "EUR", New(-3, "EUR")},
+		{"below half rounds down", New(100, "USD"), "JPY", New(151, "JPY")},
+		{"above half rounds up", New(1, "USD"), "JPY", New(2, "JPY")},
+		{"more decimals", New(100, "USD"), "KWD", New(307, "KWD")},
+		{"fewer decimals", New(151, "JPY"), "USD", New(100, "USD")},
+		{"cross rate", New(100, "EUR"), "JPY", New(303, "JPY")},
+		{"same currency", New(12345, "USD"), "USD", New(12345, "USD")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := rates.Convert(tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Convert(%s %s, %s) = %+v, want %+v", tt.from, tt.from.Currency, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	rates, err := ParseRates("USD", []string{"JPY=151.3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := rates.Convert(New(100, "USD"), "EUR"); !errors.Is(err, ErrNoRate) {
+		t.Errorf("missing target rate: got %v", err)
+	}
+	if _, _, err := rates.Convert(New(100, "EUR"), "USD"); !errors.Is(err, ErrNoRate) {
+		t.Errorf("missing source rate: got %v", err)
+	}
+	if _, _, err := rates.Convert(New(math.MaxInt64, "USD"), "JPY"); !errors.Is(err, ErrAmountOutOfRange) {
+		t.Errorf("overflow: got %v", err)
+	}
+}
@@ -1,24 +1,51 @@
 package routes
 
 import (
+	"go-webapi-example/config"
 	"go-webapi-example/controllers"
 	"go-webapi-example/middleware"
+	"go-webapi-example/models"
+	"go-webapi-example/storage"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(r *gin.Engine, db *gorm.DB) {
+func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 	// 初始化控制器
-	userController := controllers.NewUserController(db)
+	userController := controllers.NewUserController(db, cfg)
 	productController := controllers.NewProductController(db)
-	authController := controllers.NewAuthController(db)
+	authController := controllers.NewAuthController(db, cfg)
+	twoFactorController := controllers.NewTwoFactorController(db, cfg)
+	apiKeyController := controllers.NewAPIKeyController(db)
+	roleController := controllers.NewRoleController(db)
+	organizationController := controllers.NewOrganizationController(db)
+	categoryController := controllers.NewCategoryController(db)
+	imageController := controllers.NewImageController(db, cfg, storage.New(cfg))
+	inventoryController := controllers.NewInventoryController(db)
+	reservationController := controllers.NewReservationController(db, cfg)
+	priceController := controllers.NewPriceController(db, cfg)
+	priceHistoryController := controllers.NewPriceHistoryController(db, cfg)
+	cartController := controllers.NewCartController(db, cfg)
+	orderController := controllers.NewOrderController(db, cfg)
+
+	// 本地存储的文件由本服务提供访问
+	if cfg.StorageDriver != "s3" {
+		r.Static("/media", cfg.StorageLocalDir)
+	}
 
 	// 认证路由（不需要JWT）
 	auth := r.Group("/api/v1/auth")
 	{
 		auth.POST("/login", authController.Login)
+		auth.POST("/login/2fa", authController.LoginTwoFactor)
 		auth.POST("/register", authController.Register)
+		auth.POST("/refresh", authController.Refresh)
+		auth.POST("/logout", middleware.AuthMiddleware(db), authController.Logout)
+		auth.POST("/forgot-password", authController.ForgotPassword)
+		auth.POST("/reset-password", authController.ResetPassword)
+		auth.GET("/verify-email", authController.VerifyEmail)
+		auth.POST("/resend-verification", authController.ResendVerification)
 	}
 
 	// API 版本 v1
@@ -26,34 +53,160 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 	{
 		// 公开的用户路由（需要管理员权限）
 		users := v1.Group("/users")
-		users.Use(middleware.AuthMiddleware())
+		users.Use(middleware.AuthMiddleware(db), middleware.TwoFactorMiddleware(cfg), middleware.TenantMiddleware(db))
 		{
 			users.GET("/profile", userController.GetProfile)
-			users.GET("", middleware.AdminMiddleware(), userController.GetUsers)
-			users.GET("/:id", middleware.AdminMiddleware(), userController.GetUser)
-			users.PUT("/:id", userController.UpdateUser) // 用户可以更新自己的信息
-			users.DELETE("/:id", middleware.AdminMiddleware(), userController.DeleteUser)
+			users.PUT("/profile/password", userController.ChangePassword)
+			users.GET("/profile/api-keys", apiKeyController.GetAPIKeys)
+			users.POST("/profile/api-keys", apiKeyController.CreateAPIKey)
+			users.DELETE("/profile/api-keys/:keyId", apiKeyController.RevokeAPIKey)
+			users.GET("", middleware.RequirePermission(models.PermUsersRead), userController.GetUsers)
+			users.GET("/:id", middleware.RequirePermission(models.PermUsersRead), userController.GetUser)
+			users.PUT("/:id", userController.UpdateUser) // 用户可以更新自己的信息，管理员可以更新任意用户
+			users.DELETE("/:id", middleware.RequirePermission(models.PermUsersDelete), userController.DeleteUser)
+		}
+
+		// 二步验证注册路由（只需要认证，角色强制要求二步验证的用户也可以访问）
+		twoFactor := v1.Group("/users/profile/2fa")
+		twoFactor.Use(middleware.AuthMiddleware(db))
+		{
+			twoFactor.POST("/setup", twoFactorController.Setup)
+			twoFactor.POST("/enable", twoFactorController.Enable)
+			twoFactor.POST("/disable", twoFactorController.Disable)
+			twoFactor.POST("/recovery-codes", twoFactorController.RegenerateRecoveryCodes)
+		}
+
+		// 组织路由（组织由路径参数指定，不经过租户中间件）
+		organizations := v1.Group("/organizations")
+		organizations.Use(middleware.AuthMiddleware(db), middleware.TwoFactorMiddleware(cfg))
+		{
+			organizations.GET("", organizationController.GetOrganizations)
+			organizations.POST("", middleware.RequirePermission(models.PermOrgsManage), organizationController.CreateOrganization)
+			organizations.PUT("/:id/settings", organizationController.UpdateOrganizationSettings)
+			organizations.GET("/:id/members", organizationController.GetMembers)
+			organizations.POST("/:id/members", organizationController.AddMember)
+			organizations.PUT("/:id/members/:userId", organizationController.UpdateMember)
+			organizations.DELETE("/:id/members/:userId", organizationController.RemoveMember)
 		}
 
 		// 产品路由（需要认证）
 		products := v1.Group("/products")
-		products.Use(middleware.AuthMiddleware())
+		products.Use(middleware.AuthMiddleware(db), middleware.TwoFactorMiddleware(cfg), middleware.TenantMiddleware(db))
+		{
+			products.POST("", middleware.RequirePermission(models.PermProductsWrite), productController.CreateProduct)
+			products.GET("", middleware.RequirePermission(models.PermProductsRead), productController.GetProducts)
+			products.GET("/search", middleware.RequirePermission(models.PermProductsRead), productController.SearchProducts)
+			products.GET("/facets", middleware.RequirePermission(models.PermProductsRead), productController.GetProductFacets)
+			products.GET("/:id", middleware.RequirePermission(models.PermProductsRead), productController.GetProduct)
+			products.PUT("/:id", middleware.RequirePermission(models.PermProductsWrite), productController.UpdateProduct)
+			products.DELETE("/:id", middleware.RequirePermission(models.PermProductsDelete), productController.DeleteProduct)
+			products.PUT("/:id/categories", middleware.RequirePermission(models.PermProductsWrite), productController.SetProductCategories)
+			products.POST("/:id/tags", middleware.RequirePermission(models.PermProductsWrite), productController.AddProductTags)
+			products.DELETE("/:id/tags/:tag", middleware.RequirePermission(models.PermProductsWrite), productController.RemoveProductTag)
+			products.GET("/:id/images", middleware.RequirePermission(models.PermProductsRead), imageController.GetProductImages)
+			products.POST("/:id/images", middleware.RequirePermission(models.PermProductsWrite), imageController.UploadProductImage)
+			products.PUT("/:id/images/order", middleware.RequirePermission(models.PermProductsWrite), imageController.ReorderProductImages)
+			products.PUT("/:id/images/:imageId/primary", middleware.RequirePermission(models.PermProductsWrite), imageController.SetPrimaryProductImage)
+			products.DELETE("/:id/images/:imageId", middleware.RequirePermission(models.PermProductsWrite), imageController.DeleteProductImage)
+			products.POST("/:id/stock/increment", middleware.RequirePermission(models.PermProductsWrite), inventoryController.IncrementStock)
+			products.POST("/:id/stock/decrement", middleware.RequirePermission(models.PermProductsWrite), inventoryController.DecrementStock)
+			products.GET("/:id/stock/history", middleware.RequirePermission(models.PermProductsRead), inventoryController.GetStockHistory)
+			products.GET("/:id/prices", middleware.RequirePermission(models.PermProductsRead), priceController.GetProductPrices)
+			products.PUT("/:id/prices", middleware.RequirePermission(models.PermProductsWrite), priceController.SetProductPrices)
+			products.GET("/:id/price", middleware.RequirePermission(models.PermProductsRead), priceController.GetProductPriceQuote)
+			products.GET("/:id/price-history", middleware.RequirePermission(models.PermProductsRead), priceHistoryController.GetPriceHistory)
+			products.GET("/:id/scheduled-prices", middleware.RequirePermission(models.PermProductsRead), priceHistoryController.GetScheduledPrices)
+			products.POST("/:id/scheduled-prices", middleware.RequirePermission(models.PermProductsWrite), priceHistoryController.SchedulePrice)
+			products.POST("/:id/scheduled-prices/:scheduledId/cancel", middleware.RequirePermission(models.PermProductsWrite), priceHistoryController.CancelScheduledPrice)
+			products.POST("/:id/reservations", middleware.RequirePermission(models.PermOrdersWrite), reservationController.ReserveStock)
+		}
+
+		// 库存预留路由（预留用户或拥有 products:manage 权限的管理员可以操作）
+		reservations := v1.Group("/reservations")
+		reservations.Use(middleware.AuthMiddleware(db), middleware.TwoFactorMiddleware(cfg), middleware.TenantMiddleware(db), middleware.RequirePermission(models.PermProductsRead))
+		{
+			reservations.GET("/:id", reservationController.GetReservation)
+			reservations.POST("/:id/confirm", middleware.RequirePermission(models.PermOrdersWrite), reservationController.ConfirmReservation)
+			reservations.POST("/:id/release", middleware.RequirePermission(models.PermOrdersWrite), reservationController.ReleaseReservation)
+		}
+
+		// 购物车路由（当前用户的购物车）
+		cart := v1.Group("/cart")
+		cart.Use(middleware.AuthMiddleware(db), middleware.TwoFactorMiddleware(cfg), middleware.TenantMiddleware(db), middleware.RequirePermission(models.PermProductsRead))
 		{
-			products.POST("", productController.CreateProduct)
-			products.GET("", productController.GetProducts)
-			products.GET("/:id", productController.GetProduct)
-			products.PUT("/:id", productController.UpdateProduct)
-			products.DELETE("/:id", productController.DeleteProduct)
+			cart.GET("", cartController.GetCart)
+			cart.DELETE("", cartController.ClearCart)
+			cart.POST("/items", cartController.AddCartItem)
+			cart.PUT("/items/:itemId", cartController.UpdateCartItem)
+			cart.DELETE("/items/:itemId", cartController.RemoveCartItem)
+			cart.POST("/merge", cartController.MergeCart)
+		}
+
+		// 匿名购物车路由（不需要认证，组织由 X-Org-ID 指定，购物车由 X-Cart-Token 指定）
+		guestCart := v1.Group("/guest-cart")
+		guestCart.Use(middleware.GuestTenantMiddleware(db))
+		{
+			guestCart.POST("", cartController.CreateGuestCart)
+			guestCart.GET("", cartController.GetCart)
+			guestCart.DELETE("", cartController.ClearCart)
+			guestCart.POST("/items", cartController.AddCartItem)
+			guestCart.PUT("/items/:itemId", cartController.UpdateCartItem)
+			guestCart.DELETE("/items/:itemId", cartController.RemoveCartItem)
+		}
+
+		// 订单路由（当前用户的订单，下单和取消需要 orders:write，拥有 orders:manage 权限的管理员可以查看任意订单）
+		orders := v1.Group("/orders")
+		orders.Use(middleware.AuthMiddleware(db), middleware.TwoFactorMiddleware(cfg), middleware.TenantMiddleware(db), middleware.RequirePermission(models.PermProductsRead))
+		{
+			orders.GET("", orderController.GetMyOrders)
+			orders.POST("", middleware.RequirePermission(models.PermOrdersWrite), orderController.CreateOrder)
+			orders.POST("/checkout", middleware.RequirePermission(models.PermOrdersWrite), orderController.Checkout)
+			orders.GET("/:id", orderController.GetOrder)
+			orders.POST("/:id/cancel", middleware.RequirePermission(models.PermOrdersWrite), orderController.CancelOrder)
+		}
+
+		// 分类路由（需要认证）
+		categories := v1.Group("/categories")
+		categories.Use(middleware.AuthMiddleware(db), middleware.TwoFactorMiddleware(cfg), middleware.TenantMiddleware(db), middleware.RequirePermission(models.PermProductsRead))
+		{
+			categories.GET("", categoryController.GetCategories)
+			categories.GET("/:id", categoryController.GetCategory)
 		}
 
 		// 管理员路由
 		admin := v1.Group("/admin")
-		admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+		admin.Use(middleware.AuthMiddleware(db), middleware.TwoFactorMiddleware(cfg), middleware.TenantMiddleware(db))
+		{
+			admin.POST("/users", middleware.RequirePermission(models.PermUsersWrite), userController.CreateUser) // 管理员创建用户
+			admin.POST("/users/:id/verification/resend", middleware.RequirePermission(models.PermUsersManage), userController.ResendVerification)
+			admin.POST("/users/:id/verify", middleware.RequirePermission(models.PermUsersManage), userController.ForceVerify)
+			admin.POST("/users/:id/unlock", middleware.RequirePermission(models.PermUsersManage), userController.UnlockUser)
+			admin.GET("/lockout-events", middleware.RequirePermission(models.PermUsersManage), userController.GetLockoutEvents)
+			admin.PUT("/users/:id/status", middleware.RequirePermission(models.PermUsersActivate), userController.UpdateUserStatus)
+			admin.POST("/categories", middleware.RequirePermission(models.PermCategoriesManage), categoryController.CreateCategory)
+			admin.PUT("/categories/:id", middleware.RequirePermission(models.PermCategoriesManage), categoryController.UpdateCategory)
+			admin.DELETE("/categories/:id", middleware.RequirePermission(models.PermCategoriesManage), categoryController.DeleteCategory)
+			admin.GET("/orders", middleware.RequirePermission(models.PermOrdersManage), orderController.GetOrders)
+			admin.POST("/orders/:id/transitions", middleware.RequirePermission(models.PermOrdersManage), orderController.TransitionOrder)
+		}
+
+		// 角色和权限管理路由（默认只有超级管理员拥有 roles:manage 权限）
+		roles := v1.Group("/admin")
+		roles.Use(middleware.AuthMiddleware(db), middleware.TwoFactorMiddleware(cfg), middleware.TenantMiddleware(db), middleware.RequirePermission(models.PermRolesManage))
 		{
-			admin.POST("/users", userController.CreateUser) // 管理员创建用户
+			roles.GET("/permissions", roleController.GetPermissions)
+			roles.GET("/roles", roleController.GetRoles)
+			roles.POST("/roles", roleController.CreateRole)
+			roles.PUT("/roles/:id", roleController.UpdateRole)
+			roles.DELETE("/roles/:id", roleController.DeleteRole)
+			roles.PUT("/users/:id/role", userController.AssignRole)
+			roles.POST("/superadmin/transfer", userController.TransferSuperAdmin)
 		}
 	}
 
+	// 公钥集合（JWKS）
+	r.GET("/.well-known/jwks.json", authController.JWKS)
+
 	// 健康检查
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
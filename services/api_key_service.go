@@ -0,0 +1,112 @@
+package services
+
+import (
+	"errors"
+	"go-webapi-example/models"
+	"go-webapi-example/tenant"
+	"go-webapi-example/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// apiKeyPrefix API 密钥明文前缀，便于在日志和密钥扫描中识别
+const apiKeyPrefix = "wak_"
+
+// apiKeyTouchInterval 最近使用时间的更新间隔，避免每个请求都写数据库
+const apiKeyTouchInterval = time.Minute
+
+var (
+	ErrInvalidAPIKey    = errors.New("invalid, expired or revoked API key")
+	ErrAPIKeyExpiryPast = errors.New("expires_at must be in the future")
+)
+
+type APIKeyService struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyService API 密钥属于账户而不是组织，服务使用跳过租户隔离的会话
+func NewAPIKeyService(db *gorm.DB) *APIKeyService {
+	return &APIKeyService{db: tenant.System(db)}
+}
+
+// CreateAPIKey 为用户创建 API 密钥，返回的明文密钥只会出现这一次
+func (s *APIKeyService) CreateAPIKey(userID uint, req *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrAPIKeyExpiryPast
+	}
+
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	rawKey := apiKeyPrefix + secret
+
+	apiKey := &models.APIKey{
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    rawKey[:len(apiKeyPrefix)+8],
+		KeyHash:   utils.HashToken(rawKey),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.db.Create(apiKey).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.CreateAPIKeyResponse{APIKey: *apiKey, Key: rawKey}, nil
+}
+
+// GetAPIKeys 获取用户的全部 API 密钥（不含明文）
+func (s *APIKeyService) GetAPIKeys(userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey 撤销用户自己的 API 密钥
+func (s *APIKeyService) RevokeAPIKey(userID, keyID uint) error {
+	result := s.db.Model(&models.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Authenticate 校验 API 密钥并返回所属的有效用户
+func (s *APIKeyService) Authenticate(rawKey string) (*models.User, *models.APIKey, error) {
+	var apiKey models.APIKey
+	if err := s.db.Where("key_hash = ? AND revoked_at IS NULL", utils.HashToken(rawKey)).First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if apiKey.ExpiresAt != nil && !now.Before(*apiKey.ExpiresAt) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	var user models.User
+	if err := s.db.Where("is_active = ?", true).First(&user, apiKey.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.db.Model(&apiKey).UpdateColumn("last_used_at", now).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &user, &apiKey, nil
+}
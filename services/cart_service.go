@@ -0,0 +1,479 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-webapi-example/config"
+	"go-webapi-example/models"
+	"go-webapi-example/money"
+	"go-webapi-example/tenant"
+	"go-webapi-example/utils"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCartNotFound      = errors.New("cart not found or expired")
+	ErrCartItemNotFound  = errors.New("cart item not found")
+	ErrCartFull          = errors.New("cart cannot hold more than 100 different items")
+	ErrTooManyGuestCarts = errors.New("too many guest carts created from this address, please retry later")
+)
+
+// maxCartItems 购物车最多的行项目数，maxCartQuantity 单个行项目的最大数量
+const (
+	maxCartItems    = 100
+	maxCartQuantity = 999
+)
+
+// CartOwner 购物车的所有者：登录用户，或持有匿名购物车令牌的访客
+type CartOwner struct {
+	UserID uint
+	Token  string
+}
+
+type CartService struct {
+	db     *gorm.DB
+	cfg    *config.Config
+	prices *PriceService
+}
+
+func NewCartService(db *gorm.DB, cfg *config.Config) *CartService {
+	return &CartService{db: db, cfg: cfg, prices: NewPriceService(db, cfg)}
+}
+
+// ForTenant 返回限定在指定组织内的服务副本
+func (s *CartService) ForTenant(orgID uint) *CartService {
+	return &CartService{db: tenant.Scope(s.db, orgID), cfg: s.cfg, prices: s.prices.ForTenant(orgID)}
+}
+
+// CreateGuestCart 创建匿名购物车，令牌只在创建时返回一次。同一 IP 在 GUEST_CART_RATE_WINDOW 内
+// 最多创建 GUEST_CART_RATE_LIMIT 个（跨组织统计）
+func (s *CartService) CreateGuestCart(req *models.CreateCartRequest, clientIP string) (*models.Cart, error) {
+	currency := s.cfg.DefaultCurrency
+	if req.Currency != "" {
+		currency = strings.ToUpper(req.Currency)
+	}
+	if !money.Valid(currency) {
+		return nil, fmt.Errorf("%w: %q", money.ErrUnknownCurrency, currency)
+	}
+
+	var created int64
+	if err := tenant.System(s.db).Model(&models.Cart{}).
+		Where("created_ip = ? AND created_at > ?", clientIP, time.Now().Add(-s.cfg.GuestCartRateWindow)).
+		Count(&created).Error; err != nil {
+		return nil, err
+	}
+	if int(created) >= s.cfg.GuestCartRateLimit {
+		return nil, ErrTooManyGuestCarts
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	cart := &models.Cart{TokenHash: utils.HashToken(token), CreatedIP: clientIP, Currency: currency}
+	if err := s.db.Create(cart).Error; err != nil {
+		return nil, err
+	}
+	cart.Token = token
+	cart.Items = []models.CartItem{}
+	cart.Subtotal = money.New(0, currency)
+	return cart, nil
+}
+
+// GetCart 获取购物车并按实时价格和库存重新校验行项目，登录用户没有购物车时自动创建
+func (s *CartService) GetCart(owner CartOwner) (*models.Cart, error) {
+	cart, err := s.findCart(s.db, owner)
+	if err != nil {
+		return nil, err
+	}
+	return s.revalidate(cart)
+}
+
+// AddItem 加入购物车，已有同一产品（变体）的行项目时累加数量。数量不能超过可售库存
+func (s *CartService) AddItem(owner CartOwner, req *models.AddCartItemRequest) (*models.Cart, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		cart, err := s.lockCart(tx, owner)
+		if err != nil {
+			return err
+		}
+		product, variant, available, err := purchasable(tx, req.ProductID, req.VariantID)
+		if err != nil {
+			return err
+		}
+
+		var item models.CartItem
+		err = cartLine(tx, cart.ID, req.ProductID, req.VariantID).First(&item).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		exists := err == nil
+		if !exists {
+			var count int64
+			if err := tx.Model(&models.CartItem{}).Where("cart_id = ?", cart.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count >= maxCartItems {
+				return ErrCartFull
+			}
+		}
+
+		quantity := min(item.Quantity+req.Quantity, maxCartQuantity)
+		if quantity > available {
+			return ErrInsufficientStock
+		}
+		price, err := s.prices.unitPrice(product, variant, cart.Currency)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return tx.Model(&item).Select("quantity", "price_amount", "price_currency").
+				Updates(&models.CartItem{Quantity: quantity, Price: price}).Error
+		}
+		return tx.Create(&models.CartItem{
+			CartID:    cart.ID,
+			ProductID: product.ID,
+			VariantID: req.VariantID,
+			Quantity:  quantity,
+			Price:     price,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCart(owner)
+}
+
+// UpdateItem 修改行项目数量，数量不能超过可售库存
+func (s *CartService) UpdateItem(owner CartOwner, itemID uint, req *models.UpdateCartItemRequest) (*models.Cart, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		cart, err := s.lockCart(tx, owner)
+		if err != nil {
+			return err
+		}
+		var item models.CartItem
+		if err := tx.Where("cart_id = ?", cart.ID).First(&item, itemID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCartItemNotFound
+			}
+			return err
+		}
+		_, _, available, err := purchasable(tx, item.ProductID, item.VariantID)
+		if err != nil {
+			return err
+		}
+		if req.Quantity > available {
+			return ErrInsufficientStock
+		}
+		return tx.Model(&item).Update("quantity", req.Quantity).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCart(owner)
+}
+
+// RemoveItem 删除行项目
+func (s *CartService) RemoveItem(owner CartOwner, itemID uint) (*models.Cart, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		cart, err := s.lockCart(tx, owner)
+		if err != nil {
+			return err
+		}
+		result := tx.Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}, itemID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCartItemNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCart(owner)
+}
+
+// Clear 清空购物车
+func (s *CartService) Clear(owner CartOwner) (*models.Cart, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		cart, err := s.lockCart(tx, owner)
+		if err != nil {
+			return err
+		}
+		return clearCart(tx, cart.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCart(owner)
+}
+
+// MergeGuestCart 把当前组织中的匿名购物车合并到用户的购物车：相同产品（变体）累加数量，其余行项目移入，
+// 然后删除匿名购物车。合并后超出行项目上限的行项目被丢弃
+func (s *CartService) MergeGuestCart(token string, userID uint) (*models.Cart, error) {
+	owner := CartOwner{UserID: userID}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 先锁定用户购物车，再锁定匿名购物车
+		cart, err := s.lockCart(tx, owner)
+		if err != nil {
+			return err
+		}
+		guest, err := s.lockCart(tx, CartOwner{Token: token})
+		if err != nil {
+			return err
+		}
+
+		var existing, incoming []models.CartItem
+		if err := tx.Where("cart_id = ?", cart.ID).Find(&existing).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("cart_id = ?", guest.ID).Order("id").Find(&incoming).Error; err != nil {
+			return err
+		}
+		count := len(existing)
+		for _, item := range incoming {
+			i := slices.IndexFunc(existing, func(e models.CartItem) bool {
+				return e.ProductID == item.ProductID && equalIDs(e.VariantID, item.VariantID)
+			})
+			if i >= 0 {
+				quantity := min(existing[i].Quantity+item.Quantity, maxCartQuantity)
+				if err := tx.Model(&existing[i]).Update("quantity", quantity).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if count >= maxCartItems {
+				continue
+			}
+			if err := tx.Model(&item).Update("cart_id", cart.ID).Error; err != nil {
+				return err
+			}
+			count++
+		}
+
+		if err := clearCart(tx, guest.ID); err != nil {
+			return err
+		}
+		return tx.Delete(guest).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCart(owner)
+}
+
+// MergeGuestCartOnLogin 登录时合并匿名购物车。匿名购物车属于创建时指定的组织，用户必须是该组织的成员
+func (s *CartService) MergeGuestCartOnLogin(token string, userID uint) (*models.Cart, error) {
+	var guest models.Cart
+	if err := s.guestCarts(tenant.System(s.db), token).First(&guest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCartNotFound
+		}
+		return nil, err
+	}
+	if _, err := NewOrganizationService(s.db).GetMembership(guest.OrganizationID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotMember
+		}
+		return nil, err
+	}
+	return s.ForTenant(guest.OrganizationID).MergeGuestCart(token, userID)
+}
+
+// findCart 查找所有者的购物车。登录用户没有购物车时创建一个，并发创建由唯一索引保证只有一个
+func (s *CartService) findCart(db *gorm.DB, owner CartOwner) (*models.Cart, error) {
+	var cart models.Cart
+	if owner.UserID == 0 {
+		if err := s.guestCarts(db, owner.Token).First(&cart).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrCartNotFound
+			}
+			return nil, err
+		}
+		return &cart, nil
+	}
+
+	err := db.Where("user_id = ?", owner.UserID).First(&cart).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return &cart, err
+	}
+	cart = models.Cart{UserID: &owner.UserID, Currency: s.cfg.DefaultCurrency}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&cart).Error; err != nil {
+		return nil, err
+	}
+	if cart.ID == 0 {
+		if err := db.Where("user_id = ?", owner.UserID).First(&cart).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &cart, nil
+}
+
+// lockCart 在事务中锁定购物车，串行化同一购物车的修改；同时刷新更新时间，延长匿名购物车的有效期
+func (s *CartService) lockCart(tx *gorm.DB, owner CartOwner) (*models.Cart, error) {
+	cart, err := s.findCart(tx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockForUpdate(tx).First(cart, cart.ID).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(cart).Update("updated_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// guestCarts 令牌对应的未过期匿名购物车，匿名购物车在 CART_GUEST_TTL 内没有修改即过期
+func (s *CartService) guestCarts(db *gorm.DB, token string) *gorm.DB {
+	return db.Where("token_hash = ? AND user_id IS NULL AND updated_at > ?",
+		utils.HashToken(token), time.Now().Add(-s.cfg.CartGuestTTL))
+}
+
+// revalidate 按实时价格和库存校验行项目：已删除或缺少变体的标记为 unavailable，可售库存不足的标记为 insufficient_stock，
+// 价格变化的更新为实时价格并标记为 price_changed。只有没有其他问题的行项目计入小计
+func (s *CartService) revalidate(cart *models.Cart) (*models.Cart, error) {
+	items := []models.CartItem{}
+	if err := s.db.Where("cart_id = ?", cart.ID).Order("id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.ProductID
+	}
+	var products []models.Product
+	if len(ids) > 0 {
+		if err := s.db.Preload("Variants").Where("id IN ?", ids).Find(&products).Error; err != nil {
+			return nil, err
+		}
+		if err := fillAvailability(s.db, products); err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[uint]*models.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+
+	cart.Items = items
+	cart.Subtotal = money.New(0, cart.Currency)
+	cart.ItemCount = 0
+	cart.CheckoutReady = len(items) > 0
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		cart.ItemCount += item.Quantity
+		if err := s.revalidateItem(item, byID[item.ProductID], cart.Currency); err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(item.Issues, func(issue string) bool { return issue != models.CartIssuePriceChanged }) {
+			cart.CheckoutReady = false
+			continue
+		}
+		subtotal, err := cart.Subtotal.Add(item.LineTotal)
+		if err != nil {
+			return nil, err
+		}
+		cart.Subtotal = subtotal
+	}
+	return cart, nil
+}
+
+func (s *CartService) revalidateItem(item *models.CartItem, product *models.Product, currency string) error {
+	item.LineTotal = money.New(0, currency)
+	if product == nil {
+		item.Issues = append(item.Issues, models.CartIssueUnavailable)
+		return nil
+	}
+	item.Name = product.Name
+	item.Available = product.Available
+
+	var variant *models.ProductVariant
+	if item.VariantID != nil {
+		i := slices.IndexFunc(product.Variants, func(v models.ProductVariant) bool { return v.ID == *item.VariantID })
+		if i < 0 {
+			item.Issues = append(item.Issues, models.CartIssueUnavailable)
+			return nil
+		}
+		variant = &product.Variants[i]
+		item.SKU, item.Available = variant.SKU, variant.Available
+	} else if len(product.Variants) > 0 {
+		item.Issues = append(item.Issues, models.CartIssueUnavailable)
+		return nil
+	}
+	if item.Available < item.Quantity {
+		item.Issues = append(item.Issues, models.CartIssueInsufficientStock)
+	}
+
+	price, err := s.prices.unitPrice(product, variant, currency)
+	if errors.Is(err, money.ErrNoRate) {
+		item.Issues = append(item.Issues, models.CartIssuePriceUnavailable)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if price != item.Price {
+		previous := item.Price
+		if err := s.db.Model(&models.CartItem{ID: item.ID}).Select("price_amount", "price_currency").
+			Updates(&models.CartItem{Price: price}).Error; err != nil {
+			return err
+		}
+		item.Price, item.PreviousPrice = price, &previous
+		item.Issues = append(item.Issues, models.CartIssuePriceChanged)
+	}
+	item.LineTotal, err = price.Mul(int64(item.Quantity))
+	return err
+}
+
+// purchasable 校验产品（变体）可以购买并返回当前可售库存。有变体的产品必须指定变体
+func purchasable(tx *gorm.DB, productID uint, variantID *uint) (*models.Product, *models.ProductVariant, int, error) {
+	var product models.Product
+	if err := tx.Preload("Variants").First(&product, productID).Error; err != nil {
+		return nil, nil, 0, err
+	}
+	products := []models.Product{product}
+	if err := fillAvailability(tx, products); err != nil {
+		return nil, nil, 0, err
+	}
+	product = products[0]
+
+	if variantID == nil {
+		if len(product.Variants) > 0 {
+			return nil, nil, 0, ErrVariantRequired
+		}
+		return &product, nil, product.Available, nil
+	}
+	i := slices.IndexFunc(product.Variants, func(v models.ProductVariant) bool { return v.ID == *variantID })
+	if i < 0 {
+		return nil, nil, 0, ErrUnknownVariant
+	}
+	return &product, &product.Variants[i], product.Variants[i].Available, nil
+}
+
+// cartLine 购物车中同一产品（变体）的行项目
+func cartLine(tx *gorm.DB, cartID, productID uint, variantID *uint) *gorm.DB {
+	q := tx.Where("cart_id = ? AND product_id = ?", cartID, productID)
+	if variantID == nil {
+		return q.Where("variant_id IS NULL")
+	}
+	return q.Where("variant_id = ?", *variantID)
+}
+
+func clearCart(tx *gorm.DB, cartID uint) error {
+	return tx.Where("cart_id = ?", cartID).Delete(&models.CartItem{}).Error
+}
+
+func equalIDs(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-webapi-example/models"
+	"go-webapi-example/tenant"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryExists      = errors.New("category slug already exists")
+	ErrUnknownCategory     = errors.New("category does not exist")
+	ErrCategoryCycle       = errors.New("a category cannot be moved under itself or its descendants")
+	ErrCategoryHasChildren = errors.New("category still has subcategories")
+)
+
+// CategoryService 产品分类管理，分类按组织隔离
+type CategoryService struct {
+	db *gorm.DB
+}
+
+func NewCategoryService(db *gorm.DB) *CategoryService {
+	return &CategoryService{db: db}
+}
+
+// ForTenant 返回限定在指定组织内的服务副本
+func (s *CategoryService) ForTenant(orgID uint) *CategoryService {
+	return &CategoryService{db: tenant.Scope(s.db, orgID)}
+}
+
+// GetTree 获取完整的分类树，同级分类按名称排序
+func (s *CategoryService) GetTree() ([]models.Category, error) {
+	var categories []models.Category
+	if err := s.db.Order("depth, name, id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]models.Category)
+	roots := []models.Category{}
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+		} else {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	var attach func(nodes []models.Category) []models.Category
+	attach = func(nodes []models.Category) []models.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+	return attach(roots), nil
+}
+
+// GetCategoryByID 根据ID获取分类，包含面包屑和直接下级分类
+func (s *CategoryService) GetCategoryByID(id uint) (*models.Category, error) {
+	var category models.Category
+	if err := s.db.First(&category, id).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Where("parent_id = ?", category.ID).Order("name, id").Find(&category.Children).Error; err != nil {
+		return nil, err
+	}
+
+	categories := []models.Category{category}
+	if err := s.FillBreadcrumbs(categories); err != nil {
+		return nil, err
+	}
+	return &categories[0], nil
+}
+
+// CreateCategory 创建分类
+func (s *CategoryService) CreateCategory(req *models.CreateCategoryRequest) (*models.Category, error) {
+	if err := s.checkSlug(req.Slug, 0); err != nil {
+		return nil, err
+	}
+
+	category := &models.Category{Name: req.Name, Slug: req.Slug, ParentID: req.ParentID}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		parentPath := "/"
+		if req.ParentID != nil {
+			parent, err := s.findParent(tx, *req.ParentID)
+			if err != nil {
+				return err
+			}
+			parentPath, category.Depth = parent.Path, parent.Depth+1
+		}
+
+		// 路径包含自身ID，需要先写入记录
+		category.Path = parentPath
+		if err := tx.Create(category).Error; err != nil {
+			return err
+		}
+		category.Path = parentPath + strconv.FormatUint(uint64(category.ID), 10) + "/"
+		return tx.Model(category).Update("path", category.Path).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory 修改分类名称、标识或上级分类。移动分类时同时更新全部子孙分类的路径和深度
+func (s *CategoryService) UpdateCategory(id uint, req *models.UpdateCategoryRequest) (*models.Category, error) {
+	var category models.Category
+	if err := s.db.First(&category, id).Error; err != nil {
+		return nil, err
+	}
+	if req.Slug != "" && req.Slug != category.Slug {
+		if err := s.checkSlug(req.Slug, category.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]any{}
+		if req.Name != "" {
+			updates["name"] = req.Name
+		}
+		if req.Slug != "" {
+			updates["slug"] = req.Slug
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&category).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		if req.ParentID == nil {
+			return nil
+		}
+		return s.move(tx, &category, *req.ParentID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCategoryByID(id)
+}
+
+// DeleteCategory 删除分类并解除与产品的关联，仍有下级分类时不能删除
+func (s *CategoryService) DeleteCategory(id uint) error {
+	var category models.Category
+	if err := s.db.First(&category, id).Error; err != nil {
+		return err
+	}
+
+	var children int64
+	if err := s.db.Model(&models.Category{}).Where("parent_id = ?", category.ID).Count(&children).Error; err != nil {
+		return err
+	}
+	if children > 0 {
+		return ErrCategoryHasChildren
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM product_categories WHERE category_id = ?", category.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&category).Error
+	})
+}
+
+// FindCategories 根据ID查找分类，存在不属于当前组织或不存在的ID时返回 ErrUnknownCategory
+func (s *CategoryService) FindCategories(ids []uint) ([]models.Category, error) {
+	categories := []models.Category{}
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	if err := s.db.Where("id IN ?", ids).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]bool, len(categories))
+	for _, c := range categories {
+		found[c.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownCategory, id)
+		}
+	}
+	return categories, nil
+}
+
+// FillBreadcrumbs 根据物化路径为分类填充从根分类到自身的面包屑，一次查询全部祖先
+func (s *CategoryService) FillBreadcrumbs(categories []models.Category) error {
+	var ids []uint
+	for _, c := range categories {
+		ids = append(ids, pathIDs(c.Path)...)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var ancestors []models.Category
+	if err := s.db.Where("id IN ?", ids).Find(&ancestors).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]models.Category, len(ancestors))
+	for _, a := range ancestors {
+		byID[a.ID] = a
+	}
+
+	for i := range categories {
+		crumbs := []models.CategoryCrumb{}
+		for _, id := range pathIDs(categories[i].Path) {
+			if a, ok := byID[id]; ok {
+				crumbs = append(crumbs, models.CategoryCrumb{ID: a.ID, Name: a.Name, Slug: a.Slug})
+			}
+		}
+		categories[i].Breadcrumbs = crumbs
+	}
+	return nil
+}
+
+// move 将分类移动到 parentID 下（0 为根级），不能移动到自身或子孙分类下
+func (s *CategoryService) move(tx *gorm.DB, category *models.Category, parentID uint) error {
+	newPath, depth := "/", 0
+	var newParent *uint
+	if parentID != 0 {
+		parent, err := s.findParent(tx, parentID)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(parent.Path, category.Path) {
+			return ErrCategoryCycle
+		}
+		newPath, depth, newParent = parent.Path, parent.Depth+1, &parent.ID
+	}
+	newPath += strconv.FormatUint(uint64(category.ID), 10) + "/"
+	if newPath == category.Path {
+		return nil
+	}
+
+	if err := tx.Model(category).Update("parent_id", newParent).Error; err != nil {
+		return err
+	}
+	// 自身和全部子孙分类替换路径前缀并调整深度
+	return tx.Model(&models.Category{}).Where("path LIKE ?", category.Path+"%").Updates(map[string]any{
+		"path":  gorm.Expr("? || substr(path, ?)", newPath, len(category.Path)+1),
+		"depth": gorm.Expr("depth + ?", depth-category.Depth),
+	}).Error
+}
+
+func (s *CategoryService) findParent(tx *gorm.DB, id uint) (*models.Category, error) {
+	var parent models.Category
+	if err := tx.First(&parent, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownCategory, id)
+		}
+		return nil, err
+	}
+	return &parent, nil
+}
+
+// checkSlug 检查分类标识在组织内是否已被其他分类使用
+func (s *CategoryService) checkSlug(slug string, exceptID uint) error {
+	var count int64
+	if err := s.db.Model(&models.Category{}).Where("slug = ? AND id <> ?", slug, exceptID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCategoryExists
+	}
+	return nil
+}
+
+// pathIDs 解析物化路径中的分类ID
+func pathIDs(path string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if id, err := strconv.ParseUint(part, 10, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go-webapi-example/config"
+	"go-webapi-example/models"
+	"go-webapi-example/storage"
+	"go-webapi-example/tenant"
+	"go-webapi-example/utils"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"net/http"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image type, only JPEG, PNG and GIF are allowed")
+	ErrImageTooLarge    = errors.New("image exceeds the maximum allowed size")
+	ErrInvalidImage     = errors.New("image data could not be decoded")
+	ErrImageOrder       = errors.New("image_ids must list every image of the product exactly once")
+	ErrImageNotFound    = errors.New("image not found")
+)
+
+// 允许上传的图片类型（按文件内容判断）及对应的扩展名
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// ImageService 产品图片管理：校验并保存原图，生成缩略图，维护图片顺序和主图
+type ImageService struct {
+	db    *gorm.DB
+	store storage.Storage
+	cfg   *config.Config
+}
+
+func NewImageService(db *gorm.DB, store storage.Storage, cfg *config.Config) *ImageService {
+	return &ImageService{db: db, store: store, cfg: cfg}
+}
+
+// ForTenant 返回限定在指定组织内的服务副本
+func (s *ImageService) ForTenant(orgID uint) *ImageService {
+	return &ImageService{db: tenant.Scope(s.db, orgID), store: s.store, cfg: s.cfg}
+}
+
+// ListImages 按顺序获取产品的全部图片
+func (s *ImageService) ListImages(productID uint) ([]models.ProductImage, error) {
+	var product models.Product
+	if err := s.db.First(&product, productID).Error; err != nil {
+		return nil, err
+	}
+	return s.listImages(s.db, product.ID)
+}
+
+// UploadImage 上传产品图片。图片类型根据文件内容判断而不是客户端声明的类型；
+// 新图片排在最后，产品的第一张图片或 primary 为 true 时设为主图
+func (s *ImageService) UploadImage(ctx context.Context, productID uint, data []byte, primary bool, actor *Actor) (*models.ProductImage, error) {
+	product, err := modifiableProduct(s.db, productID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > s.cfg.ImageMaxBytes {
+		return nil, ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedImage
+	}
+
+	// 先读取尺寸，避免解码像素过多的图片耗尽内存
+	meta, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if meta.Width*meta.Height > s.cfg.ImageMaxPixels {
+		return nil, ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	thumbnail, thumbExt, err := encodeThumbnail(utils.Thumbnail(img, s.cfg.ThumbnailSize), contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("products/%d/%d/%s", product.OrganizationID, product.ID, name)
+	record := &models.ProductImage{
+		ProductID:    product.ID,
+		Key:          prefix + ext,
+		ThumbnailKey: prefix + "_thumb" + thumbExt,
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Width:        meta.Width,
+		Height:       meta.Height,
+	}
+	record.URL = s.store.URL(record.Key)
+	record.ThumbnailURL = s.store.URL(record.ThumbnailKey)
+
+	if err := s.store.Put(ctx, record.Key, data, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, record.ThumbnailKey, thumbnail.Bytes(), imageContentType(thumbExt)); err != nil {
+		s.removeObjects(record)
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// 锁定产品，串行化同一产品的图片变更，保证顺序号和主图唯一
+		if _, err := modifiableProduct(lockForUpdate(tx), product.ID, actor); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", product.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", product.ID).
+			Select("COALESCE(MAX(position) + 1, 0)").Scan(&record.Position).Error; err != nil {
+			return err
+		}
+
+		record.IsPrimary = primary || count == 0
+		if record.IsPrimary {
+			if err := clearPrimary(tx, product.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Create(record).Error
+	})
+	if err != nil {
+		s.removeObjects(record)
+		return nil, err
+	}
+	return record, nil
+}
+
+// ReorderImages 按 imageIDs 的顺序重新排列产品图片
+func (s *ImageService) ReorderImages(productID uint, imageIDs []uint, actor *Actor) ([]models.ProductImage, error) {
+	var images []models.ProductImage
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := modifiableProduct(lockForUpdate(tx), productID, actor); err != nil {
+			return err
+		}
+
+		current, err := s.listImages(tx, productID)
+		if err != nil {
+			return err
+		}
+		if len(imageIDs) != len(current) {
+			return ErrImageOrder
+		}
+		known := make(map[uint]bool, len(current))
+		for _, img := range current {
+			known[img.ID] = true
+		}
+		for _, id := range imageIDs {
+			if !known[id] {
+				return ErrImageOrder
+			}
+			delete(known, id) // 同一ID出现两次时第二次找不到
+		}
+
+		for i, id := range imageIDs {
+			if err := tx.Model(&models.ProductImage{}).Where("id = ?", id).Update("position", i).Error; err != nil {
+				return err
+			}
+		}
+		images, err = s.listImages(tx, productID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// SetPrimary 将指定图片设为产品主图
+func (s *ImageService) SetPrimary(productID, imageID uint, actor *Actor) (*models.ProductImage, error) {
+	var img models.ProductImage
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := modifiableProduct(lockForUpdate(tx), productID, actor); err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", productID).First(&img, imageID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrImageNotFound
+			}
+			return err
+		}
+		if img.IsPrimary {
+			return nil
+		}
+		if err := clearPrimary(tx, productID); err != nil {
+			return err
+		}
+		img.IsPrimary = true
+		return tx.Model(&img).Update("is_primary", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &img, nil
+}
+
+// DeleteImage 删除产品图片及其存储文件。删除主图时排在最前的图片成为新的主图
+func (s *ImageService) DeleteImage(productID, imageID uint, actor *Actor) error {
+	var img models.ProductImage
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := modifiableProduct(lockForUpdate(tx), productID, actor); err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", productID).First(&img, imageID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrImageNotFound
+			}
+			return err
+		}
+		if err := tx.Delete(&img).Error; err != nil {
+			return err
+		}
+		if !img.IsPrimary {
+			return nil
+		}
+
+		var next models.ProductImage
+		err := tx.Where("product_id = ?", productID).Order("position, id").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_primary", true).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// 数据库记录删除后再清理文件，清理失败只会留下无引用的文件
+	s.removeObjects(&img)
+	return nil
+}
+
+// modifiableProduct 获取产品并校验当前用户可以修改该产品
+func modifiableProduct(tx *gorm.DB, productID uint, actor *Actor) (*models.Product, error) {
+	var product models.Product
+	if err := tx.First(&product, productID).Error; err != nil {
+		return nil, err
+	}
+	if !actor.CanModifyProduct(&product) {
+		return nil, ErrForbidden
+	}
+	return &product, nil
+}
+
+func (s *ImageService) listImages(tx *gorm.DB, productID uint) ([]models.ProductImage, error) {
+	images := []models.ProductImage{}
+	if err := tx.Where("product_id = ?", productID).Order("position, id").Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// removeObjects 尽力删除图片的原图和缩略图
+func (s *ImageService) removeObjects(img *models.ProductImage) {
+	for _, key := range []string{img.Key, img.ThumbnailKey} {
+		if err := s.store.Delete(context.Background(), key); err != nil {
+			log.Printf("Failed to delete stored image %s: %v", key, err)
+		}
+	}
+}
+
+// lockForUpdate 在事务中锁定查询到的产品行，串行化同一产品的图片变更
+func lockForUpdate(tx *gorm.DB) *gorm.DB {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+}
+
+func clearPrimary(tx *gorm.DB, productID uint) error {
+	return tx.Model(&models.ProductImage{}).Where("product_id = ? AND is_primary", productID).Update("is_primary", false).Error
+}
+
+// encodeThumbnail PNG 和 GIF 原图的缩略图保存为 PNG 以保留透明背景，其余保存为 JPEG
+func encodeThumbnail(img image.Image, contentType string) (*bytes.Buffer, string, error) {
+	var buf bytes.Buffer
+	if contentType == "image/png" || contentType == "image/gif" {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return &buf, ".png", nil
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", err
+	}
+	return &buf, ".jpg", nil
+}
+
+func imageContentType(ext string) string {
+	for contentType, e := range imageExtensions {
+		if e == ext {
+			return contentType
+		}
+	}
+	return "application/octet-stream"
+}
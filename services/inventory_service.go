@@ -0,0 +1,233 @@
+package services
+
+import (
+	"errors"
+	"go-webapi-example/listing"
+	"go-webapi-example/models"
+	"go-webapi-example/tenant"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientStock      = errors.New("insufficient stock")
+	ErrInvalidMovementType    = errors.New("movement type is not allowed for this operation")
+	ErrVariantRequired        = errors.New("product has variants, variant_id is required")
+	ErrStockManagedByVariants = errors.New("stock of a product with variants is managed through its variants")
+)
+
+// 增加和扣减库存分别允许的变动类型
+var (
+	incrementTypes = []string{models.StockMovementReceive, models.StockMovementReturn, models.StockMovementAdjust}
+	decrementTypes = []string{models.StockMovementSell, models.StockMovementAdjust}
+)
+
+// StockMovementListSpec 库存流水支持的过滤和排序
+var StockMovementListSpec = listing.Spec{
+	Filters: []listing.Filter{
+		{Param: "type", Kind: listing.String, Where: "stock_movements.type = ?"},
+		{Param: "variant_id", Kind: listing.Uint, Where: "stock_movements.variant_id = ?"},
+		{Param: "actor_id", Kind: listing.Uint, Where: "stock_movements.actor_id = ?"},
+		{Param: "created_from", Kind: listing.Time, Where: "stock_movements.created_at >= ?"},
+		{Param: "created_to", Kind: listing.Time, Where: "stock_movements.created_at <= ?"},
+	},
+	Sorts: map[string]string{
+		"id":         "stock_movements.id",
+		"created_at": "stock_movements.created_at",
+	},
+	DefaultSort: "-created_at",
+	TieBreaker:  "stock_movements.id",
+}
+
+// InventoryService 库存流水：库存只通过条件更新原子地增减，每次变动记录流水
+type InventoryService struct {
+	db *gorm.DB
+}
+
+func NewInventoryService(db *gorm.DB) *InventoryService {
+	return &InventoryService{db: db}
+}
+
+// ForTenant 返回限定在指定组织内的服务副本
+func (s *InventoryService) ForTenant(orgID uint) *InventoryService {
+	return &InventoryService{db: tenant.Scope(s.db, orgID)}
+}
+
+// Increment 增加库存（入库、退货或盘盈）
+func (s *InventoryService) Increment(productID uint, req *models.StockAdjustmentRequest, actor *Actor) (*models.StockMovement, error) {
+	if !slices.Contains(incrementTypes, req.Type) {
+		return nil, ErrInvalidMovementType
+	}
+	return s.adjust(productID, req, req.Quantity, actor)
+}
+
+// Decrement 扣减库存（售出或盘亏），库存不足时返回 ErrInsufficientStock
+func (s *InventoryService) Decrement(productID uint, req *models.StockAdjustmentRequest, actor *Actor) (*models.StockMovement, error) {
+	if !slices.Contains(decrementTypes, req.Type) {
+		return nil, ErrInvalidMovementType
+	}
+	return s.adjust(productID, req, -req.Quantity, actor)
+}
+
+// History 分页查询产品的库存流水
+func (s *InventoryService) History(productID uint, params *listing.Params) (*listing.Page[models.StockMovement], error) {
+	var product models.Product
+	if err := s.db.First(&product, productID).Error; err != nil {
+		return nil, err
+	}
+	return listing.Query[models.StockMovement](s.db.Where("stock_movements.product_id = ?", product.ID), params)
+}
+
+func (s *InventoryService) adjust(productID uint, req *models.StockAdjustmentRequest, delta int, actor *Actor) (*models.StockMovement, error) {
+	var product models.Product
+	if err := s.db.First(&product, productID).Error; err != nil {
+		return nil, err
+	}
+	if !actor.CanModifyProduct(&product) {
+		return nil, ErrForbidden
+	}
+
+	var movement *models.StockMovement
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		movement, err = applyStockChange(tx, stockChange{
+			ProductID: product.ID,
+			VariantID: req.VariantID,
+			Delta:     delta,
+			Type:      req.Type,
+			Reason:    req.Reason,
+			ActorID:   &actor.UserID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// stockChange 一次库存变动
+type stockChange struct {
+	ProductID uint
+	VariantID *uint
+	Delta     int
+	Type      string
+	Reason    string
+	ActorID   *uint
+}
+
+// applyStockChange 在事务中原子地增减库存并记录流水。库存使用 stock = stock + delta 的条件更新，
+// 条件要求扣减后不少于预留中的数量，并发扣减不会丢失更新，也不会占用已预留给其他用户的库存。
+// 有变体的产品必须指定变体，变体库存和产品的汇总库存同时变动
+func applyStockChange(tx *gorm.DB, change stockChange) (*models.StockMovement, error) {
+	// 所有库存和预留的变更都先锁定产品行，保证加锁顺序一致，并与预留的可售库存检查串行执行
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&product, change.ProductID).Error; err != nil {
+		return nil, err
+	}
+
+	var variants int64
+	if err := tx.Model(&models.ProductVariant{}).Where("product_id = ?", change.ProductID).Count(&variants).Error; err != nil {
+		return nil, err
+	}
+	if variants > 0 && change.VariantID == nil {
+		return nil, ErrVariantRequired
+	}
+
+	var stockAfter int
+	if change.VariantID != nil {
+		var variant models.ProductVariant
+		if err := tx.Where("product_id = ?", change.ProductID).First(&variant, *change.VariantID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrUnknownVariant
+			}
+			return nil, err
+		}
+		result := tx.Model(&models.ProductVariant{}).
+			Where("id = ?", variant.ID).Where(stockFloor("product_variants", "variant_id", change.Delta), change.Delta).
+			Update("stock", gorm.Expr("stock + ?", change.Delta))
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil, ErrInsufficientStock
+		}
+		if err := tx.Model(&models.ProductVariant{}).Where("id = ?", variant.ID).Pluck("stock", &stockAfter).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	productFloor := stockFloor("products", "product_id", change.Delta)
+	if change.VariantID != nil {
+		productFloor = "stock + ? >= 0" // 变体已检查过预留占用
+	}
+	result := tx.Model(&models.Product{}).
+		Where("id = ?", change.ProductID).Where(productFloor, change.Delta).
+		Update("stock", gorm.Expr("stock + ?", change.Delta))
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInsufficientStock
+	}
+	if change.VariantID == nil {
+		if err := tx.Model(&models.Product{}).Where("id = ?", change.ProductID).Pluck("stock", &stockAfter).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return recordMovement(tx, change, stockAfter)
+}
+
+// stockFloor 库存条件更新的条件：扣减后的库存不能少于预留中的数量，增加库存时只要求不为负
+func stockFloor(table, column string, delta int) string {
+	if delta >= 0 {
+		return "stock + ? >= 0"
+	}
+	return "stock + ? >= (SELECT COALESCE(SUM(r.quantity), 0) FROM stock_reservations r WHERE r." + column + " = " + table + ".id AND " + activeReservationSQLFor("r") + ")"
+}
+
+// recordMovement 记录一条库存流水，stockAfter 为变动后的库存
+func recordMovement(tx *gorm.DB, change stockChange, stockAfter int) (*models.StockMovement, error) {
+	movement := &models.StockMovement{
+		ProductID:  change.ProductID,
+		VariantID:  change.VariantID,
+		Type:       change.Type,
+		Quantity:   change.Delta,
+		StockAfter: stockAfter,
+		Reason:     change.Reason,
+		ActorID:    change.ActorID,
+	}
+	if err := tx.Create(movement).Error; err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// setProductStock 将没有变体的产品库存设为指定值，差额记为一次盘点调整
+func setProductStock(tx *gorm.DB, productID uint, stock int, actorID *uint) error {
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error; err != nil {
+		return err
+	}
+	var variants int64
+	if err := tx.Model(&models.ProductVariant{}).Where("product_id = ?", productID).Count(&variants).Error; err != nil {
+		return err
+	}
+	if variants > 0 {
+		return ErrStockManagedByVariants
+	}
+	if stock == product.Stock {
+		return nil
+	}
+	_, err := applyStockChange(tx, stockChange{
+		ProductID: productID,
+		Delta:     stock - product.Stock,
+		Type:      models.StockMovementAdjust,
+		Reason:    "stock set via product update",
+		ActorID:   actorID,
+	})
+	return err
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"fmt"
+	"go-webapi-example/config"
+	"go-webapi-example/models"
+	"go-webapi-example/tenant"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LoginThrottledError 登录被限流（渐进延迟、IP 封禁）或账户被锁定
+type LoginThrottledError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%s, retry after %d seconds", e.Reason, int(e.RetryAfter.Seconds()+0.5))
+}
+
+// LoginGuardService 登录防暴力破解：按账户和客户端 IP 统计失败次数，渐进延迟并在超过阈值后临时锁定
+type LoginGuardService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+// NewLoginGuardService 登录失败计数和锁定是账户级状态，服务使用跳过租户隔离的会话；
+// 管理接口调用前由控制器确认目标用户属于当前组织
+func NewLoginGuardService(db *gorm.DB, cfg *config.Config) *LoginGuardService {
+	return &LoginGuardService{db: tenant.System(db), cfg: cfg}
+}
+
+// CheckIP 检查客户端 IP 在统计窗口内的失败次数是否已超过阈值
+func (s *LoginGuardService) CheckIP(ip string) error {
+	since := time.Now().Add(-s.cfg.LoginFailureWindow)
+
+	var count int64
+	if err := s.db.Model(&models.LoginAttempt{}).
+		Where("ip = ? AND success = ? AND created_at > ?", ip, false, since).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) < s.cfg.LoginIPMaxFailures {
+		return nil
+	}
+
+	// 窗口内最早的一次失败过期后才会解除封禁
+	var oldest models.LoginAttempt
+	if err := s.db.Where("ip = ? AND success = ? AND created_at > ?", ip, false, since).
+		Order("created_at").First(&oldest).Error; err != nil {
+		return err
+	}
+	return &LoginThrottledError{
+		Reason:     "too many failed login attempts from this address",
+		RetryAfter: time.Until(oldest.CreatedAt.Add(s.cfg.LoginFailureWindow)),
+	}
+}
+
+// CheckAccount 检查账户是否被锁定，或仍处于上一次失败后的渐进延迟中
+func (s *LoginGuardService) CheckAccount(user *models.User) error {
+	now := time.Now()
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		return &LoginThrottledError{
+			Reason:     "account is temporarily locked",
+			RetryAfter: user.LockedUntil.Sub(now),
+		}
+	}
+
+	if user.FailedLoginCount > 0 && user.LastFailedLoginAt != nil {
+		next := user.LastFailedLoginAt.Add(s.delay(user.FailedLoginCount))
+		if now.Before(next) {
+			return &LoginThrottledError{
+				Reason:     "too many failed login attempts",
+				RetryAfter: next.Sub(now),
+			}
+		}
+	}
+
+	return nil
+}
+
+// RecordFailure 记录一次失败的登录；user 为 nil 表示邮箱不存在
+func (s *LoginGuardService) RecordFailure(user *models.User, email, ip string) error {
+	attempt := &models.LoginAttempt{Email: email, IP: ip}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+	if err := s.db.Create(attempt).Error; err != nil {
+		return err
+	}
+
+	if err := s.checkIPThreshold(ip); err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	now := time.Now()
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// 原子递增失败次数；上一次锁定已过期时重新计数
+		counter := gorm.Expr("failed_login_count + 1")
+		if user.LockedUntil != nil && !now.Before(*user.LockedUntil) {
+			counter = gorm.Expr("1")
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]any{
+			"failed_login_count":   counter,
+			"last_failed_login_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		var failures int
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).
+			Pluck("failed_login_count", &failures).Error; err != nil {
+			return err
+		}
+		if failures < s.cfg.LoginMaxFailures {
+			return nil
+		}
+
+		lockedUntil := now.Add(s.cfg.LoginLockoutDuration)
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).
+			Update("locked_until", lockedUntil).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.LockoutEvent{
+			Type:        models.LockoutEventAccountLocked,
+			UserID:      &user.ID,
+			Email:       user.Email,
+			IP:          ip,
+			Failures:    failures,
+			LockedUntil: &lockedUntil,
+		}).Error
+	})
+}
+
+// RecordSuccess 记录一次成功的登录并清空账户的失败计数
+func (s *LoginGuardService) RecordSuccess(user *models.User, ip string) error {
+	attempt := &models.LoginAttempt{Email: user.Email, IP: ip, UserID: &user.ID, Success: true}
+	if err := s.db.Create(attempt).Error; err != nil {
+		return err
+	}
+
+	if user.FailedLoginCount == 0 && user.LockedUntil == nil {
+		return nil
+	}
+	return s.resetAccount(s.db, user.ID)
+}
+
+// Unlock 管理员解锁账户
+func (s *LoginGuardService) Unlock(userID, actorID uint) error {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.resetAccount(tx, user.ID); err != nil {
+			return err
+		}
+		return tx.Create(&models.LockoutEvent{
+			Type:     models.LockoutEventUnlocked,
+			UserID:   &user.ID,
+			Email:    user.Email,
+			Failures: user.FailedLoginCount,
+			ActorID:  &actorID,
+		}).Error
+	})
+}
+
+// ListLockoutEvents 按时间倒序返回最近的锁定事件，userID 为 0 时返回全部；
+// orgID 不为 0 时只返回该组织成员的账户事件（IP 封禁事件不属于任何组织，不会返回）
+func (s *LoginGuardService) ListLockoutEvents(userID, orgID uint, limit int) ([]models.LockoutEvent, error) {
+	query := s.db.Order("created_at DESC").Limit(limit)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if orgID != 0 {
+		query = query.Where("user_id IN (SELECT user_id FROM memberships WHERE organization_id = ?)", orgID)
+	}
+
+	var events []models.LockoutEvent
+	if err := query.Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// checkIPThreshold 当 IP 的失败次数刚好达到阈值时记录封禁事件
+func (s *LoginGuardService) checkIPThreshold(ip string) error {
+	var count int64
+	if err := s.db.Model(&models.LoginAttempt{}).
+		Where("ip = ? AND success = ? AND created_at > ?", ip, false, time.Now().Add(-s.cfg.LoginFailureWindow)).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != s.cfg.LoginIPMaxFailures {
+		return nil
+	}
+
+	blockedUntil := time.Now().Add(s.cfg.LoginFailureWindow)
+	return s.db.Create(&models.LockoutEvent{
+		Type:        models.LockoutEventIPBlocked,
+		IP:          ip,
+		Failures:    int(count),
+		LockedUntil: &blockedUntil,
+	}).Error
+}
+
+func (s *LoginGuardService) resetAccount(tx *gorm.DB, userID uint) error {
+	return tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]any{
+		"failed_login_count":   0,
+		"last_failed_login_at": nil,
+		"locked_until":         nil,
+	}).Error
+}
+
+// delay 第 n 次连续失败后需要等待的时间
+func (s *LoginGuardService) delay(failures int) time.Duration {
+	d := s.cfg.LoginDelayBase
+	for i := 1; i < failures && d < s.cfg.LoginDelayMax; i++ {
+		d *= 2
+	}
+	if d > s.cfg.LoginDelayMax {
+		d = s.cfg.LoginDelayMax
+	}
+	return d
+}
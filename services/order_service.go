@@ -0,0 +1,404 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"go-webapi-example/config"
+	"go-webapi-example/listing"
+	"go-webapi-example/models"
+	"go-webapi-example/money"
+	"go-webapi-example/tenant"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTransition = errors.New("order cannot move to the requested status")
+	ErrCartEmpty         = errors.New("cart is empty")
+	ErrCartChanged       = errors.New("cart prices changed since it was last viewed, please review the cart")
+	ErrTooManyPending    = errors.New("too many unpaid orders, pay or cancel an existing order first")
+)
+
+// orderTransitions 订单状态机：pending → paid → fulfilled → completed，
+// 待支付的订单可以取消，已支付、已发货和已完成的订单可以退款。cancelled 和 refunded 为终态
+var orderTransitions = map[string][]string{
+	models.OrderPending:   {models.OrderPaid, models.OrderCancelled},
+	models.OrderPaid:      {models.OrderFulfilled, models.OrderRefunded},
+	models.OrderFulfilled: {models.OrderCompleted, models.OrderRefunded},
+	models.OrderCompleted: {models.OrderRefunded},
+}
+
+// OrderListSpec 订单列表支持的过滤和排序
+var OrderListSpec = listing.Spec{
+	Filters: []listing.Filter{
+		{Param: "status", Kind: listing.String, Where: "orders.status = ?"},
+		{Param: "user_id", Kind: listing.Uint, Where: "orders.user_id = ?"},
+		{Param: "created_from", Kind: listing.Time, Where: "orders.created_at >= ?"},
+		{Param: "created_to", Kind: listing.Time, Where: "orders.created_at <= ?"},
+	},
+	Sorts: map[string]string{
+		"id":         "orders.id",
+		"created_at": "orders.created_at",
+		"updated_at": "orders.updated_at",
+	},
+	DefaultSort: "-created_at",
+	TieBreaker:  "orders.id",
+}
+
+type OrderService struct {
+	db    *gorm.DB
+	cfg   *config.Config
+	carts *CartService
+}
+
+func NewOrderService(db *gorm.DB, cfg *config.Config) *OrderService {
+	return &OrderService{db: db, cfg: cfg, carts: NewCartService(db, cfg)}
+}
+
+// ForTenant 返回限定在指定组织内的服务副本
+func (s *OrderService) ForTenant(orgID uint) *OrderService {
+	return &OrderService{db: tenant.Scope(s.db, orgID), cfg: s.cfg, carts: s.carts.ForTenant(orgID)}
+}
+
+// orderLine 下单的一行：同一产品（变体）合并为一行。从购物车下单时 Expected 为用户最近看到的单价
+type orderLine struct {
+	ProductID uint
+	VariantID *uint
+	Quantity  int
+	Expected  *money.Money
+}
+
+// CreateOrder 直接下单：按实时价格快照产品名称和单价，并在同一事务中扣减库存
+func (s *OrderService) CreateOrder(req *models.CreateOrderRequest, actor *Actor) (*models.Order, error) {
+	currency := s.cfg.DefaultCurrency
+	if req.Currency != "" {
+		currency = strings.ToUpper(req.Currency)
+	}
+	if !money.Valid(currency) {
+		return nil, fmt.Errorf("%w: %q", money.ErrUnknownCurrency, currency)
+	}
+
+	var lines []orderLine
+	for _, item := range req.Items {
+		i := slices.IndexFunc(lines, func(l orderLine) bool {
+			return l.ProductID == item.ProductID && equalIDs(l.VariantID, item.VariantID)
+		})
+		if i >= 0 {
+			lines[i].Quantity += item.Quantity
+			continue
+		}
+		lines = append(lines, orderLine{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity})
+	}
+
+	order := &models.Order{UserID: actor.UserID, Note: req.Note}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return s.placeOrder(tx, order, lines, currency, actor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetOrder(order.ID, actor)
+}
+
+// Checkout 从当前用户的购物车下单，成功后清空购物车。单价与用户最近一次读取购物车时不同（价格已变化）时拒绝下单，
+// 用户重新读取购物车确认新价格后再下单
+func (s *OrderService) Checkout(req *models.CheckoutRequest, actor *Actor) (*models.Order, error) {
+	order := &models.Order{UserID: actor.UserID, Note: req.Note}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		cart, err := s.carts.lockCart(tx, CartOwner{UserID: actor.UserID})
+		if err != nil {
+			return err
+		}
+		var items []models.CartItem
+		if err := tx.Where("cart_id = ?", cart.ID).Order("id").Find(&items).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return ErrCartEmpty
+		}
+
+		lines := make([]orderLine, len(items))
+		for i, item := range items {
+			lines[i] = orderLine{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity, Expected: &item.Price}
+		}
+		if err := s.placeOrder(tx, order, lines, cart.Currency, actor); err != nil {
+			return err
+		}
+		return clearCart(tx, cart.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetOrder(order.ID, actor)
+}
+
+// GetOrder 获取订单及行项目和状态变更记录，只有下单用户或拥有 orders:manage 权限的管理员可以查看
+func (s *OrderService) GetOrder(id uint, actor *Actor) (*models.Order, error) {
+	var order models.Order
+	if err := s.db.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("History", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	if order.UserID != actor.UserID && !actor.Can(models.PermOrdersManage) {
+		return nil, ErrForbidden
+	}
+	return &order, nil
+}
+
+// ListOrders 分页获取订单，userID 不为 0 时只返回该用户的订单
+func (s *OrderService) ListOrders(params *listing.Params, userID uint) (*listing.Page[models.Order], error) {
+	db := s.db
+	if userID != 0 {
+		db = db.Where("orders.user_id = ?", userID)
+	}
+	return listing.Query[models.Order](db, params, "Items")
+}
+
+// Transition 推进订单状态并记录状态变更。取消和发货前退款时退回库存，发货后退款按 restock 决定是否退回。
+// 没有 orders:manage 权限的用户只能取消自己待支付的订单
+func (s *OrderService) Transition(id uint, req *models.OrderTransitionRequest, actor *Actor) (*models.Order, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		if err := lockForUpdate(tx).First(&order, id).Error; err != nil {
+			return err
+		}
+		if !actor.Can(models.PermOrdersManage) && (order.UserID != actor.UserID || req.Status != models.OrderCancelled) {
+			return ErrForbidden
+		}
+		if !slices.Contains(orderTransitions[order.Status], req.Status) {
+			return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, order.Status, req.Status)
+		}
+
+		restock := req.Status == models.OrderCancelled ||
+			(req.Status == models.OrderRefunded && (order.Status == models.OrderPaid || req.Restock))
+		return moveOrder(tx, &order, req.Status, restock, &actor.UserID, req.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetOrder(id, actor)
+}
+
+// ExpirePending 取消超过 ORDER_PENDING_TTL 仍未支付的订单并退回库存，返回取消的订单数。
+// 每个订单在所属组织的单独事务中处理，单个订单失败不影响其他订单
+func (s *OrderService) ExpirePending() (int, error) {
+	var due []models.Order
+	if err := tenant.System(s.db).Select("id", "organization_id").
+		Where("status = ? AND expires_at <= NOW()", models.OrderPending).
+		Order("expires_at, id").Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, o := range due {
+		err := tenant.Scope(s.db, o.OrganizationID).Transaction(func(tx *gorm.DB) error {
+			var order models.Order
+			if err := lockForUpdate(tx).
+				Where("status = ? AND expires_at <= NOW()", models.OrderPending).
+				First(&order, o.ID).Error; err != nil {
+				return err
+			}
+			return moveOrder(tx, &order, models.OrderCancelled, true, nil, "expired")
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue // 期间已支付或取消
+		}
+		if err != nil {
+			log.Printf("Failed to expire order %d: %v", o.ID, err)
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// RunSweeper 按 ORDER_SWEEP_INTERVAL 定期取消过期的待支付订单，直到 ctx 结束
+func (s *OrderService) RunSweeper(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.OrderSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.ExpirePending(); err != nil {
+				log.Printf("Failed to expire pending orders: %v", err)
+			} else if n > 0 {
+				log.Printf("Expired %d pending orders", n)
+			}
+		}
+	}
+}
+
+// moveOrder 在事务中把已锁定的订单推进到 status 并记录状态变更，restock 时先退回全部行项目的库存。
+// 自动取消等系统操作的 actorID 为 nil
+func moveOrder(tx *gorm.DB, order *models.Order, status string, restock bool, actorID *uint, note string) error {
+	if restock {
+		var items []models.OrderItem
+		if err := tx.Where("order_id = ?", order.ID).Find(&items).Error; err != nil {
+			return err
+		}
+		// 与下单相同按产品排序加锁
+		slices.SortFunc(items, func(a, b models.OrderItem) int { return cmp.Compare(a.ProductID, b.ProductID) })
+		var skipped []models.OrderItem
+		for _, item := range items {
+			_, err := applyStockChange(tx, stockChange{
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+				Delta:     item.Quantity,
+				Type:      models.StockMovementReturn,
+				Reason:    fmt.Sprintf("order #%d %s", order.ID, status),
+				ActorID:   actorID,
+			})
+			if restockSkippable(err) {
+				skipped = append(skipped, item)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		note = restockNote(note, skipped)
+	}
+
+	from := order.Status
+	if err := tx.Model(order).Updates(map[string]any{"status": status, "expires_at": nil}).Error; err != nil {
+		return err
+	}
+	return recordOrderStatus(tx, order.ID, from, status, actorID, note)
+}
+
+// restockSkippable 行项目的库存已无处退回：产品或变体在下单后被删除，或产品在下单后改为按变体管理而行项目没有变体。
+// 这些行跳过退回，订单状态照常变更，不能因此回滚取消、退款或过期
+func restockSkippable(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrUnknownVariant) || errors.Is(err, ErrVariantRequired)
+}
+
+// restockNote 在状态变更备注后注明没有退回库存的行项目，备注最长 500 个字符
+func restockNote(note string, skipped []models.OrderItem) string {
+	if len(skipped) == 0 {
+		return note
+	}
+	ids := make([]string, len(skipped))
+	for i, item := range skipped {
+		ids[i] = fmt.Sprintf("#%d", item.ID)
+	}
+	msg := "stock not returned for order items " + strings.Join(ids, ", ") + " (product or variant changed since the order was placed)"
+	if note != "" {
+		msg = note + "; " + msg
+	}
+	if runes := []rune(msg); len(runes) > 500 {
+		msg = string(runes[:500])
+	}
+	return msg
+}
+
+// placeOrder 在事务中创建订单和行项目并扣减库存。行项目按产品排序后依次锁定产品，
+// 与其他库存变动的加锁顺序一致；任一行库存不足时整个订单回滚。
+// 待支付订单占用库存，每个用户同时待支付的订单数受 ORDER_MAX_PENDING 限制，超过 ORDER_PENDING_TTL 未支付自动取消
+func (s *OrderService) placeOrder(tx *gorm.DB, order *models.Order, lines []orderLine, currency string, actor *Actor) error {
+	// 锁定用户行，串行化同一用户的下单，保证待支付订单数的检查不被并发请求绕过
+	if err := lockForUpdate(tenant.System(tx)).Select("id").First(&models.User{}, actor.UserID).Error; err != nil {
+		return err
+	}
+	var pending int64
+	if err := tx.Model(&models.Order{}).Where("user_id = ? AND status = ?", actor.UserID, models.OrderPending).
+		Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending >= int64(s.cfg.OrderMaxPendingUser) {
+		return ErrTooManyPending
+	}
+
+	slices.SortStableFunc(lines, func(a, b orderLine) int { return cmp.Compare(a.ProductID, b.ProductID) })
+
+	now, err := databaseNow(tx)
+	if err != nil {
+		return err
+	}
+	expiresAt := now.Add(s.cfg.OrderPendingTTL)
+	order.Status = models.OrderPending
+	order.ExpiresAt = &expiresAt
+	order.Total = money.New(0, currency)
+	if err := tx.Create(order).Error; err != nil {
+		return err
+	}
+
+	items := make([]models.OrderItem, 0, len(lines))
+	for _, line := range lines {
+		if err := lockForUpdate(tx).Select("id").First(&models.Product{}, line.ProductID).Error; err != nil {
+			return err
+		}
+		product, variant, available, err := purchasable(tx, line.ProductID, line.VariantID)
+		if err != nil {
+			return err
+		}
+		if line.Quantity > available {
+			return ErrInsufficientStock
+		}
+		price, err := s.carts.prices.unitPrice(product, variant, currency)
+		if err != nil {
+			return err
+		}
+		if line.Expected != nil && *line.Expected != price {
+			return ErrCartChanged
+		}
+		lineTotal, err := price.Mul(int64(line.Quantity))
+		if err != nil {
+			return err
+		}
+		if order.Total, err = order.Total.Add(lineTotal); err != nil {
+			return err
+		}
+
+		item := models.OrderItem{
+			OrderID:     order.ID,
+			ProductID:   product.ID,
+			VariantID:   line.VariantID,
+			ProductName: product.Name,
+			Quantity:    line.Quantity,
+			UnitPrice:   price,
+			LineTotal:   lineTotal,
+		}
+		if variant != nil {
+			item.SKU = variant.SKU
+		}
+		items = append(items, item)
+
+		if _, err := applyStockChange(tx, stockChange{
+			ProductID: line.ProductID,
+			VariantID: line.VariantID,
+			Delta:     -line.Quantity,
+			Type:      models.StockMovementSell,
+			Reason:    fmt.Sprintf("order #%d", order.ID),
+			ActorID:   &actor.UserID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Create(&items).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(order).Select("total_amount", "total_currency").Updates(order).Error; err != nil {
+		return err
+	}
+	return recordOrderStatus(tx, order.ID, "", models.OrderPending, &actor.UserID, "")
+}
+
+func recordOrderStatus(tx *gorm.DB, orderID uint, from, to string, actorID *uint, note string) error {
+	return tx.Create(&models.OrderStatusChange{
+		OrderID:    orderID,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    actorID,
+		Note:       note,
+	}).Error
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-webapi-example/models"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// TestRestockSkippable 产品在下单后删除、变体删除或产品改为按变体管理时跳过退回，订单状态照常变更；
+// 其他错误（例如数据库错误）仍然回滚状态变更
+func TestRestockSkippable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{gorm.ErrRecordNotFound, true},
+		{ErrUnknownVariant, true},
+		{ErrVariantRequired, true},
+		{fmt.Errorf("%w: 3", ErrUnknownVariant), true},
+		{ErrInsufficientStock, false},
+		{errors.New("connection reset"), false},
+	}
+	for _, tt := range tests {
+		if got := restockSkippable(tt.err); got != tt.want {
+			t.Errorf("restockSkippable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRestockNote(t *testing.T) {
+	skipped := []models.OrderItem{{ID: 4, ProductID: 1}, {ID: 9, ProductID: 2}}
+	tests := []struct {
+		name    string
+		note    string
+		skipped []models.OrderItem
+		want    string
+	}{
+		{"nothing skipped", "expired", nil, "expired"},
+		{"no note", "", skipped, "stock not returned for order items #4, #9 (product or variant changed since the order was placed)"},
+		{"with note", "expired", skipped[:1], "expired; stock not returned for order items #4 (product or variant changed since the order was placed)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := restockNote(tt.note, tt.skipped); got != tt.want {
+				t.Fatalf("restockNote = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	long := restockNote(strings.Repeat("备", 500), skipped)
+	if n := utf8.RuneCountInString(long); n != 500 {
+		t.Fatalf("long note has %d runes, want 500", n)
+	}
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"errors"
+	"go-webapi-example/models"
+	"go-webapi-example/tenant"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrOrganizationExists = errors.New("organization slug already exists")
+	ErrAlreadyMember      = errors.New("user is already a member of this organization")
+	ErrNotMember          = errors.New("user is not a member of this organization")
+	ErrLastOrgOwner       = errors.New("cannot remove or demote the last owner of the organization")
+	ErrOrgOwnerRequired   = errors.New("only organization owners can manage owners")
+)
+
+// OrganizationService 组织和成员管理。成员关系按组织隔离，跨组织的查询显式使用 tenant.System
+type OrganizationService struct {
+	db *gorm.DB
+}
+
+func NewOrganizationService(db *gorm.DB) *OrganizationService {
+	return &OrganizationService{db: db}
+}
+
+// EnsureDefault 创建默认组织，并把升级前的产品和尚未加入任何组织的用户归入默认组织
+func (s *OrganizationService) EnsureDefault() error {
+	return tenant.System(s.db).Transaction(func(tx *gorm.DB) error {
+		var org models.Organization
+		if err := tx.Where(models.Organization{Slug: models.DefaultOrganizationSlug}).
+			Attrs(models.Organization{Name: "Default"}).
+			FirstOrCreate(&org).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("UPDATE products SET organization_id = ? WHERE organization_id IS NULL OR organization_id = 0", org.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`INSERT INTO memberships (organization_id, user_id, role, created_at, updated_at)
+			SELECT ?, u.id, CASE WHEN u.role = ? THEN ? ELSE ? END, NOW(), NOW() FROM users u
+			WHERE u.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM memberships m WHERE m.user_id = u.id)`,
+			org.ID, models.RoleSuperAdmin, models.OrgRoleOwner, models.OrgRoleMember).Error
+	})
+}
+
+// GetDefault 获取默认组织
+func (s *OrganizationService) GetDefault() (*models.Organization, error) {
+	var org models.Organization
+	if err := s.db.Where("slug = ?", models.DefaultOrganizationSlug).First(&org).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// GetOrganizationByID 根据ID获取组织
+func (s *OrganizationService) GetOrganizationByID(id uint) (*models.Organization, error) {
+	var org models.Organization
+	if err := s.db.First(&org, id).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// GetAllOrganizations 获取全部组织
+func (s *OrganizationService) GetAllOrganizations() ([]models.Organization, error) {
+	var orgs []models.Organization
+	if err := s.db.Order("id").Find(&orgs).Error; err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// GetUserOrganizations 获取用户加入的全部组织
+func (s *OrganizationService) GetUserOrganizations(userID uint) ([]models.Organization, error) {
+	var orgs []models.Organization
+	if err := s.db.Where("id IN (?)", tenant.System(s.db).Model(&models.Membership{}).
+		Select("organization_id").Where("user_id = ?", userID)).
+		Order("id").Find(&orgs).Error; err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// DefaultOrganizationID 用户最早加入的组织，未加入任何组织时返回 0
+func (s *OrganizationService) DefaultOrganizationID(userID uint) (uint, error) {
+	var ids []uint
+	if err := tenant.System(s.db).Model(&models.Membership{}).
+		Where("user_id = ?", userID).Order("id").Limit(1).
+		Pluck("organization_id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return ids[0], nil
+}
+
+// GetMembership 获取用户在组织中的成员关系，不是成员时返回 gorm.ErrRecordNotFound
+func (s *OrganizationService) GetMembership(orgID, userID uint) (*models.Membership, error) {
+	var membership models.Membership
+	if err := tenant.Scope(s.db, orgID).Where("user_id = ?", userID).First(&membership).Error; err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// UpdateSettings 修改组织设置，只有 owner 或拥有 organizations:manage 权限的用户可以操作
+func (s *OrganizationService) UpdateSettings(orgID uint, req *models.UpdateOrganizationSettingsRequest, actor *Actor, actorRole string) (*models.Organization, error) {
+	if !canManageOwners(actor, actorRole) {
+		return nil, ErrOrgOwnerRequired
+	}
+	if err := s.db.Model(&models.Organization{ID: orgID}).Update("public_storefront", *req.PublicStorefront).Error; err != nil {
+		return nil, err
+	}
+	return s.GetOrganizationByID(orgID)
+}
+
+// CreateOrganization 创建组织，创建者成为组织的 owner
+func (s *OrganizationService) CreateOrganization(req *models.CreateOrganizationRequest, creatorID uint) (*models.Organization, error) {
+	var count int64
+	if err := s.db.Model(&models.Organization{}).Where("slug = ?", req.Slug).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrOrganizationExists
+	}
+
+	org := &models.Organization{Name: req.Name, Slug: req.Slug}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		return tenant.Scope(tx, org.ID).Create(&models.Membership{UserID: creatorID, Role: models.OrgRoleOwner}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// GetMembers 获取组织的全部成员
+func (s *OrganizationService) GetMembers(orgID uint) ([]models.Membership, error) {
+	var memberships []models.Membership
+	if err := tenant.Scope(s.db, orgID).Order("id").Find(&memberships).Error; err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uint, 0, len(memberships))
+	for _, m := range memberships {
+		userIDs = append(userIDs, m.UserID)
+	}
+	var users []models.User
+	if err := tenant.Scope(s.db, orgID).Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	profiles := make(map[uint]models.UserProfile, len(users))
+	for _, u := range users {
+		profiles[u.ID] = u.Profile()
+	}
+
+	// 已删除用户的成员关系不再返回
+	members := make([]models.Membership, 0, len(memberships))
+	for _, m := range memberships {
+		if profile, ok := profiles[m.UserID]; ok {
+			m.User = &profile
+			members = append(members, m)
+		}
+	}
+	return members, nil
+}
+
+// AddMember 将已有用户加入组织。只能添加与操作者同在某个组织中的用户，拥有 organizations:manage 权限时可以添加任意用户；
+// 用户不存在或不可见时都返回 gorm.ErrRecordNotFound，响应只包含成员关系，不返回用户资料
+func (s *OrganizationService) AddMember(orgID uint, req *models.AddMemberRequest, actor *Actor, actorRole string) (*models.Membership, error) {
+	if req.Role == models.OrgRoleOwner && !canManageOwners(actor, actorRole) {
+		return nil, ErrOrgOwnerRequired
+	}
+
+	visible := tenant.System(s.db).Model(&models.User{}).Where("id = ?", req.UserID)
+	if !actor.Can(models.PermOrgsManage) {
+		visible = visible.Where("id IN (SELECT m.user_id FROM memberships m JOIN memberships mine ON mine.organization_id = m.organization_id WHERE mine.user_id = ?)", actor.UserID)
+	}
+	var count int64
+	if err := visible.Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if _, err := s.GetMembership(orgID, req.UserID); err == nil {
+		return nil, ErrAlreadyMember
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	membership := &models.Membership{UserID: req.UserID, Role: req.Role}
+	if err := tenant.Scope(s.db, orgID).Create(membership).Error; err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// UpdateMemberRole 修改成员的组织内角色，涉及 owner 的变更只能由 owner 操作，且不能降级最后一个 owner
+func (s *OrganizationService) UpdateMemberRole(orgID, userID uint, role string, actor *Actor, actorRole string) (*models.Membership, error) {
+	membership, err := s.GetMembership(orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotMember
+		}
+		return nil, err
+	}
+	if membership.Role == role {
+		return membership, nil
+	}
+	if (role == models.OrgRoleOwner || membership.Role == models.OrgRoleOwner) && !canManageOwners(actor, actorRole) {
+		return nil, ErrOrgOwnerRequired
+	}
+
+	err = tenant.Scope(s.db, orgID).Transaction(func(tx *gorm.DB) error {
+		if membership.Role == models.OrgRoleOwner {
+			if err := ensureOtherOwner(tx, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(membership).Update("role", role).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// RemoveMember 将用户移出组织，不能移除最后一个 owner
+func (s *OrganizationService) RemoveMember(orgID, userID uint, actor *Actor, actorRole string) error {
+	membership, err := s.GetMembership(orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotMember
+		}
+		return err
+	}
+	if membership.Role == models.OrgRoleOwner && !canManageOwners(actor, actorRole) {
+		return ErrOrgOwnerRequired
+	}
+
+	return tenant.Scope(s.db, orgID).Transaction(func(tx *gorm.DB) error {
+		if membership.Role == models.OrgRoleOwner {
+			if err := ensureOtherOwner(tx, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(membership).Error
+	})
+}
+
+// CanManageMembers owner、admin 和拥有 organizations:manage 权限的用户可以管理组织成员
+func CanManageMembers(actor *Actor, actorRole string) bool {
+	return actorRole == models.OrgRoleOwner || actorRole == models.OrgRoleAdmin || actor.Can(models.PermOrgsManage)
+}
+
+func canManageOwners(actor *Actor, actorRole string) bool {
+	return actorRole == models.OrgRoleOwner || actor.Can(models.PermOrgsManage)
+}
+
+// ensureOtherOwner 锁定组织的全部 owner，确认除 userID 外至少还有一个
+func ensureOtherOwner(tx *gorm.DB, userID uint) error {
+	var ids []uint
+	if err := tx.Model(&models.Membership{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ?", models.OrgRoleOwner).
+		Pluck("user_id", &ids).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if id != userID {
+			return nil
+		}
+	}
+	return ErrLastOrgOwner
+}
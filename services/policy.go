@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+	"go-webapi-example/models"
+	"slices"
+)
+
+// ErrForbidden 当前用户无权操作该资源
+var ErrForbidden = errors.New("you do not have permission to modify this resource")
+
+// Actor 发起请求的用户及其权限，用于资源级别的归属校验
+type Actor struct {
+	UserID      uint
+	Role        string
+	Permissions []string
+}
+
+// Can 判断是否拥有指定权限
+func (a *Actor) Can(permission string) bool {
+	return slices.Contains(a.Permissions, permission)
+}
+
+// CanModifyProduct 产品只能由创建者或拥有 products:manage 权限的管理员修改和删除
+func (a *Actor) CanModifyProduct(product *models.Product) bool {
+	return product.UserID == a.UserID || a.Can(models.PermProductsManage)
+}
+
+// CanModifyUser 用户只能修改自己的信息，拥有 users:write 权限的管理员可以修改其他用户，超级管理员只能由超级管理员修改
+func (a *Actor) CanModifyUser(user *models.User) bool {
+	if user.ID == a.UserID {
+		return true
+	}
+	if user.Role == models.RoleSuperAdmin && a.Role != models.RoleSuperAdmin {
+		return false
+	}
+	return a.Can(models.PermUsersWrite)
+}
+
+// CanAssignRole 授予 user 以外的角色需要 roles:manage 权限，授予 superadmin 角色还必须是超级管理员本人操作
+func (a *Actor) CanAssignRole(role string) bool {
+	if role == models.RoleUser {
+		return true
+	}
+	if !a.Can(models.PermRolesManage) {
+		return false
+	}
+	return role != models.RoleSuperAdmin || a.Role == models.RoleSuperAdmin
+}
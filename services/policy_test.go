@@ -0,0 +1,30 @@
+package services
+
+import (
+	"go-webapi-example/models"
+	"testing"
+)
+
+func TestCanModifyUser(t *testing.T) {
+	admin := &Actor{UserID: 1, Role: models.RoleAdmin, Permissions: []string{models.PermUsersWrite}}
+	superAdmin := &Actor{UserID: 2, Role: models.RoleSuperAdmin, Permissions: []string{models.PermUsersWrite}}
+	member := &Actor{UserID: 3, Role: models.RoleUser}
+
+	tests := []struct {
+		name  string
+		actor *Actor
+		user  *models.User
+		want  bool
+	}{
+		{"self", member, &models.User{ID: 3, Role: models.RoleUser}, true},
+		{"other user without users:write", member, &models.User{ID: 4, Role: models.RoleUser}, false},
+		{"admin on user", admin, &models.User{ID: 4, Role: models.RoleUser}, true},
+		{"admin on superadmin", admin, &models.User{ID: 2, Role: models.RoleSuperAdmin}, false},
+		{"superadmin on superadmin", superAdmin, &models.User{ID: 5, Role: models.RoleSuperAdmin}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.actor.CanModifyUser(tt.user); got != tt.want {
+			t.Errorf("%s: CanModifyUser = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-webapi-example/config"
+	"go-webapi-example/listing"
+	"go-webapi-example/models"
+	"go-webapi-example/money"
+	"go-webapi-example/tenant"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPriceWindow     = errors.New("ends_at must be after starts_at and in the future")
+	ErrPriceWindowOverlap     = errors.New("scheduled price overlaps another pending or active scheduled price of this product")
+	ErrPriceManagedByVariants = errors.New("price of a product with variants is derived from its variants")
+	ErrScheduledPriceNotFound = errors.New("scheduled price not found")
+	ErrScheduledPriceClosed   = errors.New("scheduled price has already ended or been cancelled")
+)
+
+// PriceChangeListSpec 价格变动记录支持的过滤和排序
+var PriceChangeListSpec = listing.Spec{
+	Filters: []listing.Filter{
+		{Param: "variant_id", Kind: listing.Uint, Where: "price_changes.variant_id = ?"},
+		{Param: "source", Kind: listing.String, Where: "price_changes.source = ?"},
+		{Param: "actor_id", Kind: listing.Uint, Where: "price_changes.actor_id = ?"},
+		{Param: "created_from", Kind: listing.Time, Where: "price_changes.created_at >= ?"},
+		{Param: "created_to", Kind: listing.Time, Where: "price_changes.created_at <= ?"},
+	},
+	Sorts: map[string]string{
+		"id":         "price_changes.id",
+		"created_at": "price_changes.created_at",
+	},
+	DefaultSort: "-created_at",
+	TieBreaker:  "price_changes.id",
+}
+
+// PriceHistoryService 价格变动记录和定时价格
+type PriceHistoryService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewPriceHistoryService(db *gorm.DB, cfg *config.Config) *PriceHistoryService {
+	return &PriceHistoryService{db: db, cfg: cfg}
+}
+
+// ForTenant 返回限定在指定组织内的服务副本
+func (s *PriceHistoryService) ForTenant(orgID uint) *PriceHistoryService {
+	return &PriceHistoryService{db: tenant.Scope(s.db, orgID), cfg: s.cfg}
+}
+
+// History 分页获取产品（含变体）的价格变动记录
+func (s *PriceHistoryService) History(productID uint, params *listing.Params) (*listing.Page[models.PriceChange], error) {
+	var product models.Product
+	if err := s.db.First(&product, productID).Error; err != nil {
+		return nil, err
+	}
+	return listing.Query[models.PriceChange](s.db.Where("price_changes.product_id = ?", product.ID), params)
+}
+
+// ListScheduled 产品的全部定时价格，按开始时间排序
+func (s *PriceHistoryService) ListScheduled(productID uint) ([]models.ScheduledPrice, error) {
+	var product models.Product
+	if err := s.db.First(&product, productID).Error; err != nil {
+		return nil, err
+	}
+	scheduled := []models.ScheduledPrice{}
+	if err := s.db.Where("product_id = ?", product.ID).Order("starts_at, id").Find(&scheduled).Error; err != nil {
+		return nil, err
+	}
+	return scheduled, nil
+}
+
+// SchedulePrice 创建定时价格，只有创建者或管理员可以修改。有变体的产品价格由变体汇总，不能定时修改
+func (s *PriceHistoryService) SchedulePrice(productID uint, req *models.SchedulePriceRequest, actor *Actor) (*models.ScheduledPrice, error) {
+	if req.Price.Amount < 0 {
+		return nil, ErrNegativePrice
+	}
+	if req.EndsAt != nil && (!req.EndsAt.After(req.StartsAt) || !req.EndsAt.After(time.Now())) {
+		return nil, ErrInvalidPriceWindow
+	}
+
+	scheduled := &models.ScheduledPrice{
+		ProductID: productID,
+		Price:     req.Price,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		Status:    models.ScheduledPricePending,
+		ActorID:   &actor.UserID,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 锁定产品，串行化同一产品的定时价格
+		product, err := modifiableProduct(lockForUpdate(tx), productID, actor)
+		if err != nil {
+			return err
+		}
+		if err := checkPriceSettable(tx, product, req.Price); err != nil {
+			return err
+		}
+
+		// 没有结束时间的定时价格视为一个时间点：开始时间不能落在其他时间窗口内，
+		// 时间窗口内也不能有其他定时价格开始
+		overlap := tx.Model(&models.ScheduledPrice{}).
+			Where("product_id = ? AND status IN ?", productID, []string{models.ScheduledPricePending, models.ScheduledPriceActive})
+		cond := tx.Where("starts_at = ?", req.StartsAt).
+			Or("ends_at IS NOT NULL AND starts_at < ? AND ends_at > ?", req.StartsAt, req.StartsAt)
+		if req.EndsAt != nil {
+			cond = cond.Or("starts_at > ? AND starts_at < ?", req.StartsAt, *req.EndsAt)
+		}
+		var count int64
+		if err := overlap.Where(cond).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrPriceWindowOverlap
+		}
+
+		return tx.Create(scheduled).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scheduled, nil
+}
+
+// CancelScheduled 取消定时价格。生效中的定时价格取消时立即恢复生效前的价格
+func (s *PriceHistoryService) CancelScheduled(productID, scheduledID uint, actor *Actor) (*models.ScheduledPrice, error) {
+	var scheduled models.ScheduledPrice
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		product, err := modifiableProduct(lockForUpdate(tx), productID, actor)
+		if err != nil {
+			return err
+		}
+		if err := lockForUpdate(tx).Where("product_id = ?", product.ID).First(&scheduled, scheduledID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrScheduledPriceNotFound
+			}
+			return err
+		}
+
+		switch scheduled.Status {
+		case models.ScheduledPricePending:
+		case models.ScheduledPriceActive:
+			if err := restorePreviousPrice(tx, product, &scheduled, &actor.UserID); err != nil {
+				return err
+			}
+		default:
+			return ErrScheduledPriceClosed
+		}
+		return closeScheduledPrice(tx, &scheduled, models.ScheduledPriceCancelled, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &scheduled, nil
+}
+
+// ApplyDue 应用已到开始时间的定时价格，并结束已到结束时间的定时价格，返回处理的数量
+func (s *PriceHistoryService) ApplyDue() (int, error) {
+	now := time.Now()
+	var due []models.ScheduledPrice
+	if err := tenant.System(s.db).
+		Where("(status = ? AND starts_at <= ?) OR (status = ? AND ends_at <= ?)",
+			models.ScheduledPricePending, now, models.ScheduledPriceActive, now).
+		Order("starts_at, id").Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, scheduled := range due {
+		err := tenant.Scope(s.db, scheduled.OrganizationID).Transaction(func(tx *gorm.DB) error {
+			return advanceScheduledPrice(tx, scheduled.ProductID, scheduled.ID, now)
+		})
+		if err != nil {
+			log.Printf("Failed to apply scheduled price %d: %v", scheduled.ID, err)
+			continue
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// RunScheduler 按 PRICE_SCHEDULE_INTERVAL 定期应用定时价格，直到 ctx 结束
+func (s *PriceHistoryService) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PriceScheduleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.ApplyDue(); err != nil {
+				log.Printf("Failed to apply scheduled prices: %v", err)
+			} else if n > 0 {
+				log.Printf("Applied %d scheduled prices", n)
+			}
+		}
+	}
+}
+
+// advanceScheduledPrice 在事务中把一条定时价格推进到下一个状态：等待生效的开始生效，生效中的结束并恢复原价格
+func advanceScheduledPrice(tx *gorm.DB, productID, scheduledID uint, now time.Time) error {
+	// 与修改产品一样先锁定产品
+	var product models.Product
+	productErr := lockForUpdate(tx).First(&product, productID).Error
+	if productErr != nil && !errors.Is(productErr, gorm.ErrRecordNotFound) {
+		return productErr
+	}
+	var scheduled models.ScheduledPrice
+	if err := lockForUpdate(tx).First(&scheduled, scheduledID).Error; err != nil {
+		return err
+	}
+	if productErr != nil {
+		// 产品已删除
+		return closeScheduledPrice(tx, &scheduled, models.ScheduledPriceCancelled, now)
+	}
+
+	switch {
+	case scheduled.Status == models.ScheduledPricePending && !scheduled.StartsAt.After(now):
+		if scheduled.EndsAt != nil && !scheduled.EndsAt.After(now) {
+			// 整个时间窗口都已错过（例如服务停机），不再应用
+			return closeScheduledPrice(tx, &scheduled, models.ScheduledPriceCompleted, now)
+		}
+		if err := checkPriceSettable(tx, &product, scheduled.Price); err != nil {
+			log.Printf("Cancelling scheduled price %d: %v", scheduled.ID, err)
+			return closeScheduledPrice(tx, &scheduled, models.ScheduledPriceCancelled, now)
+		}
+		previous := product.Price
+		if err := setProductPrice(tx, &product, scheduled.Price, models.PriceChange{
+			Source: models.PriceChangeScheduled, ScheduledPriceID: &scheduled.ID, ActorID: scheduled.ActorID,
+		}); err != nil {
+			return err
+		}
+		updates := map[string]any{
+			"previous_price_amount":   previous.Amount,
+			"previous_price_currency": previous.Currency,
+			"applied_at":              now,
+			"status":                  models.ScheduledPriceActive,
+		}
+		if scheduled.EndsAt == nil {
+			updates["status"], updates["ended_at"] = models.ScheduledPriceCompleted, now
+		}
+		return tx.Model(&scheduled).Updates(updates).Error
+	case scheduled.Status == models.ScheduledPriceActive && scheduled.EndsAt != nil && !scheduled.EndsAt.After(now):
+		if err := restorePreviousPrice(tx, &product, &scheduled, scheduled.ActorID); err != nil {
+			return err
+		}
+		return closeScheduledPrice(tx, &scheduled, models.ScheduledPriceCompleted, now)
+	}
+	// 已被并发取消或处理
+	return nil
+}
+
+// restorePreviousPrice 恢复定时价格生效前的价格。生效期间价格被手动修改过时保留修改后的价格
+func restorePreviousPrice(tx *gorm.DB, product *models.Product, scheduled *models.ScheduledPrice, actorID *uint) error {
+	if product.Price != scheduled.Price {
+		return nil
+	}
+	return setProductPrice(tx, product, scheduled.PreviousPrice, models.PriceChange{
+		Source: models.PriceChangeScheduleEnded, ScheduledPriceID: &scheduled.ID, ActorID: actorID,
+	})
+}
+
+func closeScheduledPrice(tx *gorm.DB, scheduled *models.ScheduledPrice, status string, now time.Time) error {
+	scheduled.Status, scheduled.EndedAt = status, &now
+	return tx.Model(scheduled).Select("status", "ended_at").Updates(scheduled).Error
+}
+
+// checkPriceSettable 有变体的产品价格由变体汇总，价格的货币必须与产品相同
+func checkPriceSettable(tx *gorm.DB, product *models.Product, price money.Money) error {
+	var variants int64
+	if err := tx.Model(&models.ProductVariant{}).Where("product_id = ?", product.ID).Count(&variants).Error; err != nil {
+		return err
+	}
+	if variants > 0 {
+		return ErrPriceManagedByVariants
+	}
+	if price.Currency != product.Price.Currency {
+		return ErrCurrencyMismatch
+	}
+	return nil
+}
+
+// setProductPrice 修改没有变体的产品的价格并记录价格变动，调用方负责锁定产品
+func setProductPrice(tx *gorm.DB, product *models.Product, price money.Money, change models.PriceChange) error {
+	if err := tx.Model(&models.Product{}).Where("id = ?", product.ID).Updates(map[string]any{
+		"price_amount":       price.Amount,
+		"price_currency":     price.Currency,
+		"max_price_amount":   price.Amount,
+		"max_price_currency": price.Currency,
+	}).Error; err != nil {
+		return err
+	}
+	change.ProductID, change.OldPrice, change.NewPrice = product.ID, product.Price, price
+	product.Price, product.MaxPrice = price, price
+	return recordPriceChange(tx, change)
+}
+
+// recordProductPrice 把产品当前价格与 old 比较，有变化时记录一条价格变动。
+// 有变体的产品价格由变体汇总，因此在变体修改完成后读取最终价格
+func recordProductPrice(tx *gorm.DB, productID uint, old money.Money, source string, actorID *uint) error {
+	var current models.Product
+	if err := tx.Select("id", "price_amount", "price_currency").First(&current, productID).Error; err != nil {
+		return err
+	}
+	return recordPriceChange(tx, models.PriceChange{
+		ProductID: productID, OldPrice: old, NewPrice: current.Price, Source: source, ActorID: actorID,
+	})
+}
+
+// recordPriceChange 记录一条价格变动，价格没有变化时忽略
+func recordPriceChange(tx *gorm.DB, change models.PriceChange) error {
+	if change.OldPrice == change.NewPrice {
+		return nil
+	}
+	return tx.Create(&change).Error
+}
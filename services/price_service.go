@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-webapi-example/config"
+	"go-webapi-example/models"
+	"go-webapi-example/money"
+	"go-webapi-example/tenant"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNegativePrice     = errors.New("price must not be negative")
+	ErrDuplicateCurrency = errors.New("each currency can appear only once in a price list")
+	ErrBaseCurrencyPrice = errors.New("the product currency is set through the product price, not the price list")
+)
+
+type PriceService struct {
+	db    *gorm.DB
+	rates *money.Rates
+}
+
+func NewPriceService(db *gorm.DB, cfg *config.Config) *PriceService {
+	return &PriceService{db: db, rates: cfg.ExchangeRates}
+}
+
+// ForTenant 返回限定在指定组织内的服务副本
+func (s *PriceService) ForTenant(orgID uint) *PriceService {
+	return &PriceService{db: tenant.Scope(s.db, orgID), rates: s.rates}
+}
+
+// ListPrices 产品的价格表，按货币代码排序
+func (s *PriceService) ListPrices(productID uint) ([]models.ProductPrice, error) {
+	if err := s.db.Select("id").First(&models.Product{}, productID).Error; err != nil {
+		return nil, err
+	}
+	prices := []models.ProductPrice{}
+	if err := s.db.Where("product_id = ?", productID).Order("price_currency").Find(&prices).Error; err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// SetPrices 整体替换产品的价格表，只有创建者或管理员可以修改
+func (s *PriceService) SetPrices(productID uint, prices []money.Money, actor *Actor) ([]models.ProductPrice, error) {
+	seen := make(map[string]bool, len(prices))
+	for _, price := range prices {
+		if price.Amount < 0 {
+			return nil, ErrNegativePrice
+		}
+		if seen[price.Currency] {
+			return nil, ErrDuplicateCurrency
+		}
+		seen[price.Currency] = true
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		product, err := modifiableProduct(lockForUpdate(tx), productID, actor)
+		if err != nil {
+			return err
+		}
+		if seen[product.Price.Currency] {
+			return ErrBaseCurrencyPrice
+		}
+		if err := tx.Where("product_id = ?", productID).Delete(&models.ProductPrice{}).Error; err != nil {
+			return err
+		}
+		if len(prices) == 0 {
+			return nil
+		}
+		records := make([]models.ProductPrice, len(prices))
+		for i, price := range prices {
+			records[i] = models.ProductPrice{ProductID: productID, Price: price}
+		}
+		return tx.Create(&records).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.ListPrices(productID)
+}
+
+// Quote 产品在指定货币下的价格：与产品货币相同时直接返回产品价格，其次使用价格表，
+// 价格表中没有该货币时按本地汇率表换算
+func (s *PriceService) Quote(productID uint, currency string) (*models.PriceQuote, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !money.Valid(currency) {
+		return nil, fmt.Errorf("%w: %q", money.ErrUnknownCurrency, currency)
+	}
+
+	var product models.Product
+	if err := s.db.First(&product, productID).Error; err != nil {
+		return nil, err
+	}
+	return s.quote(&product, currency)
+}
+
+func (s *PriceService) quote(product *models.Product, currency string) (*models.PriceQuote, error) {
+	quote := &models.PriceQuote{ProductID: product.ID, BasePrice: product.Price}
+	if currency == product.Price.Currency {
+		quote.Price, quote.Source = product.Price, models.PriceSourceBase
+		return quote, nil
+	}
+
+	var listed models.ProductPrice
+	err := s.db.Where("product_id = ? AND price_currency = ?", product.ID, currency).First(&listed).Error
+	if err == nil {
+		quote.Price, quote.Source = listed.Price, models.PriceSourceList
+		return quote, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	converted, rate, err := s.rates.Convert(product.Price, currency)
+	if err != nil {
+		return nil, err
+	}
+	quote.Price, quote.Source, quote.Rate = converted, models.PriceSourceConverted, rate.FloatString(8)
+	return quote, nil
+}
+
+// unitPrice 产品（变体）在指定货币下的单价。变体价格没有单独的价格表，货币不同时按汇率换算
+func (s *PriceService) unitPrice(product *models.Product, variant *models.ProductVariant, currency string) (money.Money, error) {
+	if variant == nil {
+		quote, err := s.quote(product, currency)
+		if err != nil {
+			return money.Money{}, err
+		}
+		return quote.Price, nil
+	}
+	converted, _, err := s.rates.Convert(variant.Price, currency)
+	return converted, err
+}
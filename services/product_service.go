@@ -1,11 +1,57 @@
 package services
 
 import (
+	"go-webapi-example/listing"
 	"go-webapi-example/models"
+	"go-webapi-example/money"
+	"go-webapi-example/tenant"
+	"html"
+	"strconv"
+	"strings"
 
 	"gorm.io/gorm"
 )
 
+// productPriceSQL 产品价格的主单位数值。价格以最小货币单位保存，不同货币的小数位数不同，
+// 价格过滤和分面统计按主单位比较。不同货币的价格不能直接比较，价格过滤和排序要求同时指定 currency
+var productPriceSQL = "(products.price_amount / power(10, " + money.ScaleSQL("products.price_currency") + "))"
+
+// ProductListSpec 产品列表支持的过滤和排序字段
+var ProductListSpec = listing.Spec{
+	Filters: []listing.Filter{
+		{Param: "min_price", Kind: listing.Float, Where: productPriceSQL + " >= ?", Requires: "currency"},
+		{Param: "max_price", Kind: listing.Float, Where: productPriceSQL + " <= ?", Requires: "currency"},
+		{Param: "currency", Kind: listing.String, Where: "products.price_currency = upper(?)"},
+		{Param: "in_stock", Kind: listing.Bool, Where: "(products.stock > 0) = ?"},
+		{Param: "owner_id", Kind: listing.Uint, Where: "products.user_id = ?"},
+		{Param: "created_from", Kind: listing.Time, Where: "products.created_at >= ?"},
+		{Param: "created_to", Kind: listing.Time, Where: "products.created_at <= ?"},
+		// 包含子孙分类中的产品
+		{Param: "category_id", Kind: listing.Uint, Where: `products.id IN (SELECT pc.product_id FROM product_categories pc
+			JOIN categories c ON c.id = pc.category_id
+			JOIN categories root ON c.path LIKE root.path || '%'
+			WHERE root.id = ?)`},
+		// tags 匹配任意一个标签，tags_all 需要带有全部标签
+		{Param: "tags", Kind: listing.Keywords, Where: `products.id IN (SELECT pt.product_id FROM product_tags pt
+			JOIN tags t ON t.id = pt.tag_id WHERE t.name IN ?)`},
+		{Param: "tags_all", Kind: listing.Keywords, Where: `products.id IN (SELECT pt.product_id FROM product_tags pt
+			JOIN tags t ON t.id = pt.tag_id WHERE t.name IN ?
+			GROUP BY pt.product_id HAVING COUNT(DISTINCT t.id) = ?)`,
+			Args: func(value any) []any { return []any{value, len(value.([]string))} }},
+	},
+	Sorts: map[string]string{
+		"id":         "products.id",
+		"name":       "products.name",
+		"price":      "products.price_amount", // 按价格排序要求指定 currency，同一货币内直接比较最小货币单位，游标分页可以使用该列
+		"stock":      "products.stock",
+		"created_at": "products.created_at",
+		"updated_at": "products.updated_at",
+	},
+	SortRequires: map[string]string{"price": "currency"},
+	DefaultSort:  "-created_at",
+	TieBreaker:   "products.id",
+}
+
 type ProductService struct {
 	db *gorm.DB
 }
@@ -14,43 +60,256 @@ func NewProductService(db *gorm.DB) *ProductService {
 	return &ProductService{db: db}
 }
 
-func (s *ProductService) CreateProduct(req *models.CreateProductRequest) (*models.Product, error) {
+// ForTenant 返回限定在指定组织内的服务副本；产品按组织隔离，未指定组织的查询会直接报错
+func (s *ProductService) ForTenant(orgID uint) *ProductService {
+	return &ProductService{db: tenant.Scope(s.db, orgID)}
+}
+
+// CreateProduct 创建产品，创建者为当前登录用户。同时提交变体时产品的价格区间和库存由变体汇总
+func (s *ProductService) CreateProduct(req *models.CreateProductRequest, userID uint) (*models.Product, error) {
 	product := &models.Product{
 		Name:        req.Name,
 		Description: req.Description,
-		Price:       req.Price,
 		Stock:       req.Stock,
-		UserID:      req.UserID,
+		UserID:      userID,
+	}
+	if err := checkVariantRequests(req.Variants); err != nil {
+		return nil, err
+	}
+	if req.Price != nil {
+		product.Price = *req.Price
+	} else if len(req.Variants) > 0 {
+		product.Price = money.New(0, req.Variants[0].Price.Currency) // 由变体汇总
+	}
+	if product.Price.Amount < 0 {
+		return nil, ErrNegativePrice
+	}
+	product.MaxPrice = product.Price
+	if len(req.Variants) > 0 {
+		product.Stock = 0 // 由变体汇总
 	}
 
-	if err := s.db.Create(product).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		if len(req.Variants) > 0 {
+			if err := replaceVariants(tx, product.ID, req.Variants, &userID); err != nil {
+				return err
+			}
+		}
+		if err := recordProductPrice(tx, product.ID, money.Money{}, models.PriceChangeCreated, &userID); err != nil {
+			return err
+		}
+		if product.Stock == 0 {
+			return nil
+		}
+		_, err := recordMovement(tx, stockChange{
+			ProductID: product.ID,
+			Delta:     product.Stock,
+			Type:      models.StockMovementReceive,
+			Reason:    "initial stock",
+			ActorID:   &userID,
+		}, product.Stock)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	if len(req.Variants) > 0 {
+		return s.GetProductByID(product.ID)
+	}
+	product.Available = product.Stock
 	return product, nil
 }
 
 func (s *ProductService) GetProductByID(id uint) (*models.Product, error) {
 	var product models.Product
-	if err := s.db.Preload("User").First(&product, id).Error; err != nil {
+	if err := s.db.Preload("User").Preload("Categories").Preload("Tags").
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }).
+		Preload("Prices", func(db *gorm.DB) *gorm.DB { return db.Order("price_currency") }).
+		First(&product, id).Error; err != nil {
+		return nil, err
+	}
+	if err := NewCategoryService(s.db).FillBreadcrumbs(product.Categories); err != nil {
+		return nil, err
+	}
+	products := []models.Product{product}
+	if err := fillAvailability(s.db, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+// ListProducts 按列表参数查询一页产品，同时返回符合过滤条件的总数
+func (s *ProductService) ListProducts(params *listing.Params) (*listing.Page[models.Product], error) {
+	page, err := listing.Query[models.Product](s.db, params, "User", "Categories", "Tags")
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []models.Category
+	for _, p := range page.Items {
+		categories = append(categories, p.Categories...)
+	}
+	if err := NewCategoryService(s.db).FillBreadcrumbs(categories); err != nil {
+		return nil, err
+	}
+	// 按顺序写回各产品的分类
+	for i := range page.Items {
+		n := len(page.Items[i].Categories)
+		page.Items[i].Categories, categories = categories[:n], categories[n:]
+	}
+	if err := s.attachImages(page.Items); err != nil {
+		return nil, err
+	}
+	if err := s.attachVariants(page.Items); err != nil {
+		return nil, err
+	}
+	if err := fillAvailability(s.db, page.Items); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// SetProductCategories 整体替换产品所属分类，只有创建者或管理员可以修改
+func (s *ProductService) SetProductCategories(id uint, categoryIDs []uint, actor *Actor) (*models.Product, error) {
+	product, err := s.GetProductByID(id)
+	if err != nil {
 		return nil, err
 	}
-	return &product, nil
+	if !actor.CanModifyProduct(product) {
+		return nil, ErrForbidden
+	}
+
+	categories, err := NewCategoryService(s.db).FindCategories(categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(product).Association("Categories").Replace(categories); err != nil {
+		return nil, err
+	}
+	return s.GetProductByID(id)
 }
 
-func (s *ProductService) GetAllProducts() ([]models.Product, error) {
-	var products []models.Product
-	if err := s.db.Preload("User").Find(&products).Error; err != nil {
+// AddProductTags 为产品添加标签，不存在的标签自动创建，只有创建者或管理员可以修改
+func (s *ProductService) AddProductTags(id uint, names []string, actor *Actor) (*models.Product, error) {
+	product, err := s.GetProductByID(id)
+	if err != nil {
 		return nil, err
 	}
-	return products, nil
+	if !actor.CanModifyProduct(product) {
+		return nil, ErrForbidden
+	}
+
+	tags, err := NewTagService(s.db).FindOrCreate(names)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(product).Association("Tags").Append(tags); err != nil {
+		return nil, err
+	}
+	return s.GetProductByID(id)
 }
 
-func (s *ProductService) UpdateProduct(id uint, req *models.UpdateProductRequest) (*models.Product, error) {
+// RemoveProductTag 移除产品的标签，产品没有该标签时不做任何修改
+func (s *ProductService) RemoveProductTag(id uint, name string, actor *Actor) (*models.Product, error) {
 	product, err := s.GetProductByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if !actor.CanModifyProduct(product) {
+		return nil, ErrForbidden
+	}
+
+	tag, err := NewTagService(s.db).FindByName(name)
+	if err == gorm.ErrRecordNotFound {
+		return product, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(product).Association("Tags").Delete(tag); err != nil {
+		return nil, err
+	}
+	return s.GetProductByID(id)
+}
+
+// DefaultPriceBuckets 分面统计默认的价格区间边界
+var DefaultPriceBuckets = []float64{0, 50, 100, 200, 500, 1000, 5000}
+
+// maxTagFacets 分面统计最多返回的标签数量
+const maxTagFacets = 50
+
+// GetFacets 按当前过滤条件统计产品总数、各标签的产品数和各价格区间的产品数。
+// bounds 为升序的价格区间边界，结果包含低于第一个边界和不低于最后一个边界的开放区间。
+// 不同货币的价格不能放在同一组区间里，调用方只在按 currency 过滤时传入 bounds，bounds 为空时不统计价格区间
+func (s *ProductService) GetFacets(params *listing.Params, bounds []float64) (*models.ProductFacetsResponse, error) {
+	facets := &models.ProductFacetsResponse{Tags: []models.TagFacet{}}
+	if err := params.Filter(s.db.Model(&models.Product{})).Count(&facets.Total).Error; err != nil {
+		return nil, err
+	}
+
+	if err := params.Filter(s.db.Model(&models.Product{})).
+		Joins("JOIN product_tags ON product_tags.product_id = products.id").
+		Joins("JOIN tags ON tags.id = product_tags.tag_id").
+		Select("tags.name AS name, COUNT(DISTINCT products.id) AS count").
+		Group("tags.name").Order("count DESC, name").Limit(maxTagFacets).
+		Scan(&facets.Tags).Error; err != nil {
+		return nil, err
+	}
+	if len(bounds) == 0 {
+		return facets, nil
+	}
+
+	// width_bucket 返回 0 表示低于第一个边界，len(bounds) 表示不低于最后一个边界。
+	// 切片参数会被展开为 (a, b)，边界数组直接写入 SQL（均为已解析的数值）
+	thresholds := make([]string, len(bounds))
+	for i, b := range bounds {
+		thresholds[i] = strconv.FormatFloat(b, 'f', -1, 64)
+	}
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	if err := params.Filter(s.db.Model(&models.Product{})).
+		Select("width_bucket(" + productPriceSQL + ", ARRAY[" + strings.Join(thresholds, ",") + "]::float8[]) AS bucket, COUNT(*) AS count").
+		Group("bucket").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[int]int64, len(rows))
+	for _, r := range rows {
+		counts[r.Bucket] = r.Count
+	}
+
+	for i := 0; i <= len(bounds); i++ {
+		bucket := models.PriceBucket{Count: counts[i]}
+		if i > 0 {
+			bucket.Min = &bounds[i-1]
+		}
+		if i < len(bounds) {
+			bucket.Max = &bounds[i]
+		}
+		// 价格不会为负，省略没有产品的开放下区间
+		if i == 0 && bucket.Count == 0 {
+			continue
+		}
+		facets.PriceBuckets = append(facets.PriceBuckets, bucket)
+	}
+	return facets, nil
+}
+
+// UpdateProduct 更新产品，只有创建者或管理员可以修改
+func (s *ProductService) UpdateProduct(id uint, req *models.UpdateProductRequest, actor *Actor) (*models.Product, error) {
+	product, err := s.GetProductByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanModifyProduct(product) {
+		return nil, ErrForbidden
+	}
 
 	if req.Name != "" {
 		product.Name = req.Name
@@ -58,20 +317,268 @@ func (s *ProductService) UpdateProduct(id uint, req *models.UpdateProductRequest
 	if req.Description != "" {
 		product.Description = req.Description
 	}
-	if req.Price > 0 {
-		product.Price = req.Price
+	oldPrice := product.Price
+	if req.Price != nil {
+		if req.Price.Amount < 0 {
+			return nil, ErrNegativePrice
+		}
+		// 有变体时更换货币需要同时提交使用新货币的变体
+		if req.Price.Currency != product.Price.Currency && len(product.Variants) > 0 && req.Variants == nil {
+			return nil, ErrCurrencyMismatch
+		}
+		product.Price, product.MaxPrice = *req.Price, *req.Price
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// 库存不随产品一起保存，只通过库存流水变动，避免覆盖并发的库存变动
+		if err := tx.Omit("Categories", "Tags", "Images", "Variants", "Prices", "Stock").Save(product).Error; err != nil {
+			return err
+		}
+		// 有变体的产品价格始终由变体汇总
+		if req.Variants != nil {
+			if err := replaceVariants(tx, product.ID, req.Variants, &actor.UserID); err != nil {
+				return err
+			}
+		} else if err := syncVariantTotals(tx, product.ID); err != nil {
+			return err
+		}
+		if err := recordProductPrice(tx, product.ID, oldPrice, models.PriceChangeManual, &actor.UserID); err != nil {
+			return err
+		}
+		if req.Stock != nil {
+			return setProductStock(tx, product.ID, *req.Stock, &actor.UserID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetProductByID(product.ID)
+}
+
+// DeleteProduct 删除产品，只有创建者或管理员可以删除
+func (s *ProductService) DeleteProduct(id uint, actor *Actor) error {
+	var product models.Product
+	if err := s.db.First(&product, id).Error; err != nil {
+		return err
+	}
+	if !actor.CanModifyProduct(&product) {
+		return ErrForbidden
+	}
+	// 变体直接删除，释放其 SKU
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", product.ID).Delete(&models.ProductVariant{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&product).Error
+	})
+}
+
+// attachImages 一次查询当前页全部产品的图片并按顺序写回
+func (s *ProductService) attachImages(products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	var images []models.ProductImage
+	if err := s.db.Where("product_id IN ?", ids).Order("position, id").Find(&images).Error; err != nil {
+		return err
 	}
-	if req.Stock >= 0 {
-		product.Stock = req.Stock
+	byProduct := make(map[uint][]models.ProductImage)
+	for _, img := range images {
+		byProduct[img.ProductID] = append(byProduct[img.ProductID], img)
 	}
+	for i := range products {
+		products[i].Images = byProduct[products[i].ID]
+	}
+	return nil
+}
 
-	if err := s.db.Save(product).Error; err != nil {
+// attachVariants 一次查询当前页全部产品的变体并按顺序写回
+func (s *ProductService) attachVariants(products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	var variants []models.ProductVariant
+	if err := s.db.Where("product_id IN ?", ids).Order("position, id").Find(&variants).Error; err != nil {
+		return err
+	}
+	byProduct := make(map[uint][]models.ProductVariant)
+	for _, v := range variants {
+		byProduct[v.ProductID] = append(byProduct[v.ProductID], v)
+	}
+	for i := range products {
+		products[i].Variants = byProduct[products[i].ID]
+	}
+	return nil
+}
+
+// 产品搜索的全文检索配置与 products.search_vector 生成列保持一致
+// ts_headline 先用私有区字符标记匹配位置，对文本做 HTML 转义后再替换为 <mark> 标签，
+// 产品名称和描述中的 HTML 因此不会原样出现在高亮结果中
+const (
+	markStart = "\uE000"
+	markStop  = "\uE001"
+)
+
+const (
+	searchQuery      = "websearch_to_tsquery('english', ?)"
+	searchHighlight  = "StartSel=" + markStart + ", StopSel=" + markStop + ", HighlightAll=true"
+	searchSnippet    = "StartSel=" + markStart + ", StopSel=" + markStop + ", MaxFragments=2, MaxWords=20, MinWords=5"
+	searchMatchFull  = "fulltext"
+	searchMatchTrgm  = "trigram"
+	searchSnippetLen = 80
+)
+
+// ProductSearchSpec 产品搜索支持的过滤条件，结果按相关度排序
+var ProductSearchSpec = listing.Spec{Filters: ProductListSpec.Filters}
+
+// SearchProducts 搜索产品名称和描述：先用 tsvector 全文检索并按 ts_rank 排序；
+// 没有结果时（拼写错误、中文等无法分词的文本）退回 pg_trgm 相似度和子串匹配
+func (s *ProductService) SearchProducts(q string, params *listing.Params) (*listing.Page[models.ProductSearchResult], error) {
+	// 统计和查询各自构建语句，避免复用已执行过的 *gorm.DB
+	match := searchMatchFull
+	scope := func() *gorm.DB {
+		return params.Filter(s.db.Model(&models.Product{})).
+			Where("products.search_vector @@ "+searchQuery, q)
+	}
+
+	page := &listing.Page[models.ProductSearchResult]{Items: []models.ProductSearchResult{}}
+	if err := scope().Count(&page.Total).Error; err != nil {
 		return nil, err
 	}
+	if page.Total == 0 {
+		match = searchMatchTrgm
+		pattern := "%" + escapeLike(q) + "%"
+		scope = func() *gorm.DB {
+			return params.Filter(s.db.Model(&models.Product{})).
+				Where("products.name % ? OR ? <% products.description OR products.name ILIKE ? OR products.description ILIKE ?", q, q, pattern, pattern)
+		}
+		if err := scope().Count(&page.Total).Error; err != nil {
+			return nil, err
+		}
+	}
 
-	return product, nil
+	if page.Total == 0 {
+		return page, nil
+	}
+
+	query := scope()
+	if match == searchMatchFull {
+		query = query.Select("products.*, ts_rank(products.search_vector, "+searchQuery+") AS rank, "+
+			"ts_headline('english', products.name, "+searchQuery+", ?) AS name_highlight, "+
+			"ts_headline('english', products.description, "+searchQuery+", ?) AS description_highlight",
+			q, q, searchHighlight, q, searchSnippet)
+	} else {
+		query = query.Select("products.*, GREATEST(similarity(products.name, ?), word_similarity(?, products.description)) AS rank", q, q)
+	}
+	if err := query.Order("rank DESC").Order("products.id").
+		Limit(params.Limit).Offset(params.Offset).
+		Find(&page.Items).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.attachUsers(page.Items); err != nil {
+		return nil, err
+	}
+	products := make([]models.Product, len(page.Items))
+	for i := range page.Items {
+		products[i] = page.Items[i].Product
+	}
+	if err := fillAvailability(s.db, products); err != nil {
+		return nil, err
+	}
+	for i := range page.Items {
+		result := &page.Items[i]
+		result.Available = products[i].Available
+		result.Match = match
+		if match == searchMatchTrgm {
+			result.NameHighlight = highlight(result.Name, q, 0)
+			result.DescriptionHighlight = highlight(result.Description, q, searchSnippetLen)
+		} else {
+			result.NameHighlight = markHeadline(result.NameHighlight)
+			result.DescriptionHighlight = markHeadline(result.DescriptionHighlight)
+		}
+	}
+	return page, nil
+}
+
+// attachUsers 为搜索结果补充创建用户信息
+func (s *ProductService) attachUsers(results []models.ProductSearchResult) error {
+	userIDs := make([]uint, 0, len(results))
+	for _, r := range results {
+		userIDs = append(userIDs, r.UserID)
+	}
+	var users []models.User
+	if err := s.db.Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]models.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	for i := range results {
+		results[i].User = byID[results[i].UserID]
+	}
+	return nil
+}
+
+// markHeadline 转义 ts_headline 的结果，再把匹配标记替换为 <mark> 标签
+func markHeadline(headline string) string {
+	return strings.NewReplacer(markStart, "<mark>", markStop, "</mark>").Replace(html.EscapeString(headline))
+}
+
+// highlight 用 <mark> 标记 text 中第一次出现的 q（不区分大小写），其余文本做 HTML 转义；
+// width 大于 0 时只返回匹配位置附近约 width 个字符的片段
+func highlight(text, q string, width int) string {
+	runes := []rune(text)
+	needle := []rune(strings.ToLower(q))
+	lower := []rune(strings.ToLower(text))
+	start := -1
+	if len(lower) == len(runes) {
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if string(lower[i:i+len(needle)]) == string(needle) {
+				start = i
+				break
+			}
+		}
+	}
+
+	if start < 0 {
+		if width > 0 && len(runes) > width {
+			return html.EscapeString(string(runes[:width])) + "…"
+		}
+		return html.EscapeString(text)
+	}
+
+	end := start + len(needle)
+	from, to := 0, len(runes)
+	if width > 0 && len(runes) > width {
+		from = max(start-(width-len(needle))/2, 0)
+		to = min(from+width, len(runes))
+	}
+	snippet := html.EscapeString(string(runes[from:start])) + "<mark>" + html.EscapeString(string(runes[start:end])) + "</mark>" +
+		html.EscapeString(string(runes[end:to]))
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(runes) {
+		snippet += "…"
+	}
+	return snippet
 }
 
-func (s *ProductService) DeleteProduct(id uint) error {
-	return s.db.Delete(&models.Product{}, id).Error
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
 }
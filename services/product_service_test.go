@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"go-webapi-example/listing"
+	"go-webapi-example/models"
+	"go-webapi-example/money"
+	"go-webapi-example/tenant"
+	"net/url"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestHighlightEscapesHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		q     string
+		width int
+		want  string
+	}{
+		{"match", "iPhone 15", "iphone", 0, "<mark>iPhone</mark> 15"},
+		{"escape around match", `<b>Pro</b> & "Max"`, "pro", 0, `&lt;b&gt;<mark>Pro</mark>&lt;/b&gt; &amp; &#34;Max&#34;`},
+		{"escape match", "a<script>b", "<script>", 0, "a<mark>&lt;script&gt;</mark>b"},
+		{"no match", `<img src=x onerror=alert(1)>`, "phone", 0, `&lt;img src=x onerror=alert(1)&gt;`},
+		{"no match truncated", "<i>abcdef</i>", "zz", 4, "&lt;i&gt;a…"},
+		{"snippet", "0123456789<x>", "<x>", 6, "…9<mark>&lt;x&gt;</mark>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight(tt.text, tt.q, tt.width); got != tt.want {
+				t.Errorf("highlight(%q, %q, %d) = %q, want %q", tt.text, tt.q, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkHeadline(t *testing.T) {
+	tests := []struct {
+		headline string
+		want     string
+	}{
+		{markStart + "iPhone" + markStop + " 15", "<mark>iPhone</mark> 15"},
+		{"<script>" + markStart + "alert" + markStop + "</script>", "&lt;script&gt;<mark>alert</mark>&lt;/script&gt;"},
+		{"Tom & Jerry", "Tom &amp; Jerry"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := markHeadline(tt.headline); got != tt.want {
+			t.Errorf("markHeadline(%q) = %q, want %q", tt.headline, got, tt.want)
+		}
+	}
+}
+
+// TestListSpecsSupportCursor 每个排序字段都必须对应模型字段，游标分页才能生成边界条件
+func TestListSpecsSupportCursor(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tenant.Register(db); err != nil {
+		t.Fatal(err)
+	}
+	db = tenant.Scope(db, 1)
+
+	specs := []struct {
+		name  string
+		spec  listing.Spec
+		query func(*listing.Params) error
+	}{
+		{"products", ProductListSpec, func(p *listing.Params) error { _, err := listing.Query[models.Product](db, p); return err }},
+		{"users", UserListSpec, func(p *listing.Params) error { _, err := listing.Query[models.User](db, p); return err }},
+		{"orders", OrderListSpec, func(p *listing.Params) error { _, err := listing.Query[models.Order](db, p); return err }},
+		{"stock movements", StockMovementListSpec, func(p *listing.Params) error { _, err := listing.Query[models.StockMovement](db, p); return err }},
+		{"price changes", PriceChangeListSpec, func(p *listing.Params) error { _, err := listing.Query[models.PriceChange](db, p); return err }},
+	}
+	for _, s := range specs {
+		for sort := range s.spec.Sorts {
+			t.Run(s.name+" "+sort, func(t *testing.T) {
+				values := url.Values{"cursor": {""}, "sort": {"-" + sort}}
+				if required := s.spec.SortRequires[sort]; required != "" {
+					values.Set(required, "USD")
+				}
+				p, err := listing.Parse(values, s.spec)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := s.query(p); err != nil {
+					t.Fatalf("cursor query sorted by %s: %v", sort, err)
+				}
+			})
+		}
+	}
+}
+
+func TestCheckVariantRequests(t *testing.T) {
+	price := func(amount int64, currency string) *money.Money {
+		m := money.New(amount, currency)
+		return &m
+	}
+	tests := []struct {
+		name string
+		reqs []models.ProductVariantRequest
+		want error
+	}{
+		{"valid", []models.ProductVariantRequest{
+			{SKU: "A", Options: map[string]string{"size": "S"}, Price: price(999, "USD")},
+			{SKU: "B", Options: map[string]string{"size": "M"}, Price: price(1099, "USD")},
+		}, nil},
+		{"missing price", []models.ProductVariantRequest{{SKU: "A"}}, ErrVariantPrice},
+		{"missing currency", []models.ProductVariantRequest{{SKU: "A", Price: price(999, "")}}, ErrVariantPrice},
+		{"unknown currency", []models.ProductVariantRequest{{SKU: "A", Price: price(999, "XXX")}}, ErrVariantPrice},
+		{"zero price", []models.ProductVariantRequest{{SKU: "A", Price: price(0, "USD")}}, ErrVariantPrice},
+		{"negative price", []models.ProductVariantRequest{{SKU: "A", Price: price(-1, "USD")}}, ErrVariantPrice},
+		{"duplicate sku", []models.ProductVariantRequest{
+			{SKU: "A", Options: map[string]string{"size": "S"}, Price: price(999, "USD")},
+			{SKU: "A", Options: map[string]string{"size": "M"}, Price: price(999, "USD")},
+		}, ErrDuplicateVariant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkVariantRequests(tt.reqs); !errors.Is(err, tt.want) {
+				t.Fatalf("checkVariantRequests: got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-webapi-example/models"
+	"go-webapi-example/tenant"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrRoleExists          = errors.New("role already exists")
+	ErrUnknownRole         = errors.New("role does not exist")
+	ErrSystemRoleImmutable = errors.New("built-in roles cannot be deleted and superadmin permissions cannot be changed")
+	ErrRoleInUse           = errors.New("role is still assigned to users")
+	ErrUnknownPermission   = errors.New("unknown permission")
+)
+
+// RBACService 角色和权限管理
+type RBACService struct {
+	db *gorm.DB
+}
+
+func NewRBACService(db *gorm.DB) *RBACService {
+	return &RBACService{db: db}
+}
+
+// SeedDefaults 写入权限目录和内置角色。已存在的内置角色保留管理员修改过的权限，
+// 只补充本次新加入目录的默认权限；superadmin 始终同步为全部权限
+func (s *RBACService) SeedDefaults() error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var existing []string
+		if err := tx.Model(&models.Permission{}).Pluck("name", &existing).Error; err != nil {
+			return err
+		}
+
+		catalog := make([]models.Permission, len(models.PermissionCatalog))
+		copy(catalog, models.PermissionCatalog)
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"description"}),
+		}).Create(&catalog).Error; err != nil {
+			return err
+		}
+
+		var all []models.Permission
+		if err := tx.Find(&all).Error; err != nil {
+			return err
+		}
+
+		for _, name := range []string{models.RoleUser, models.RoleAdmin, models.RoleSuperAdmin} {
+			var role models.Role
+			err := tx.Where("name = ?", name).First(&role).Error
+			if err == nil {
+				if name == models.RoleSuperAdmin {
+					if err := tx.Model(&role).Association("Permissions").Replace(all); err != nil {
+						return err
+					}
+					continue
+				}
+
+				var added []string
+				for _, p := range models.DefaultRolePermissions[name] {
+					if !slices.Contains(existing, p) {
+						added = append(added, p)
+					}
+				}
+				if len(added) == 0 {
+					continue
+				}
+				permissions, err := s.findPermissions(tx, added)
+				if err != nil {
+					return err
+				}
+				if err := tx.Model(&role).Association("Permissions").Append(permissions); err != nil {
+					return err
+				}
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			role = models.Role{Name: name, System: true, Permissions: all}
+			if name != models.RoleSuperAdmin {
+				if role.Permissions, err = s.findPermissions(tx, models.DefaultRolePermissions[name]); err != nil {
+					return err
+				}
+			}
+			if err := tx.Create(&role).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetPermissions 获取全部权限
+func (s *RBACService) GetPermissions() ([]models.Permission, error) {
+	var permissions []models.Permission
+	if err := s.db.Order("name").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// GetRoles 获取全部角色及其权限
+func (s *RBACService) GetRoles() ([]models.Role, error) {
+	var roles []models.Role
+	if err := s.db.Preload("Permissions").Order("id").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GetRoleByID 根据ID获取角色
+func (s *RBACService) GetRoleByID(id uint) (*models.Role, error) {
+	var role models.Role
+	if err := s.db.Preload("Permissions").First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// RoleExists 判断角色名称是否存在
+func (s *RBACService) RoleExists(name string) (bool, error) {
+	var count int64
+	if err := s.db.Model(&models.Role{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// PermissionsForRole 获取角色拥有的权限标识，角色不存在时返回空列表
+func (s *RBACService) PermissionsForRole(name string) ([]string, error) {
+	permissions := []string{}
+	err := s.db.Model(&models.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name = ?", name).
+		Order("permissions.name").
+		Pluck("permissions.name", &permissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// CreateRole 创建自定义角色
+func (s *RBACService) CreateRole(req *models.CreateRoleRequest) (*models.Role, error) {
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	exists, err := s.RoleExists(name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrRoleExists
+	}
+
+	permissions, err := s.findPermissions(s.db, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{Name: name, Description: req.Description, Permissions: permissions}
+	if err := s.db.Create(role).Error; err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// UpdateRole 更新角色说明并整体替换权限。权限有变化时在同一事务中递增该角色全部用户的令牌版本，
+// 已签发的访问令牌（携带旧的权限列表）立即失效，用户刷新令牌后按新权限签发
+func (s *RBACService) UpdateRole(id uint, req *models.UpdateRoleRequest) (*models.Role, error) {
+	role, err := s.GetRoleByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == models.RoleSuperAdmin {
+		return nil, ErrSystemRoleImmutable
+	}
+
+	permissions, err := s.findPermissions(s.db, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := len(permissions) != len(role.Permissions)
+	for _, p := range role.Permissions {
+		if !slices.ContainsFunc(permissions, func(q models.Permission) bool { return q.ID == p.ID }) {
+			changed = true
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Update("description", req.Description).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(role).Association("Permissions").Replace(permissions); err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+		return tenant.System(tx).Unscoped().Model(&models.User{}).Where("role = ?", role.Name).
+			UpdateColumn("token_version", gorm.Expr("token_version + 1")).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetRoleByID(id)
+}
+
+// DeleteRole 删除自定义角色，内置角色和仍被用户使用的角色不能删除
+func (s *RBACService) DeleteRole(id uint) error {
+	role, err := s.GetRoleByID(id)
+	if err != nil {
+		return err
+	}
+	if role.System {
+		return ErrSystemRoleImmutable
+	}
+
+	var users int64
+	if err := tenant.System(s.db).Model(&models.User{}).Where("role = ?", role.Name).Count(&users).Error; err != nil {
+		return err
+	}
+	if users > 0 {
+		return ErrRoleInUse
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(role).Error
+	})
+}
+
+// findPermissions 根据权限标识查找权限，存在未知标识时返回错误
+func (s *RBACService) findPermissions(tx *gorm.DB, names []string) ([]models.Permission, error) {
+	permissions := []models.Permission{}
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	if err := tx.Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		found[p.Name] = true
+	}
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("%w %q", ErrUnknownPermission, name)
+		}
+	}
+	return permissions, nil
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-webapi-example/config"
+	"go-webapi-example/models"
+	"go-webapi-example/tenant"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrReservationNotActive = errors.New("reservation is no longer active")
+	ErrReservationExpired   = errors.New("reservation has expired")
+	ErrTooManyReservations  = errors.New("too many active reservations, confirm or release an existing reservation first")
+)
+
+// activeReservationSQL 仍然占用库存的预留：状态为 active 且未过期（后台任务可能尚未处理已过期的预留）
+var activeReservationSQL = activeReservationSQLFor("")
+
+func activeReservationSQLFor(alias string) string {
+	if alias != "" {
+		alias += "."
+	}
+	return alias + "status = 'active' AND " + alias + "expires_at > NOW()"
+}
+
+// ReservationService 库存预留：结账期间占用可售库存，确认时扣减库存，超时自动释放
+type ReservationService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewReservationService(db *gorm.DB, cfg *config.Config) *ReservationService {
+	return &ReservationService{db: db, cfg: cfg}
+}
+
+// ForTenant 返回限定在指定组织内的服务副本
+func (s *ReservationService) ForTenant(orgID uint) *ReservationService {
+	return &ReservationService{db: tenant.Scope(s.db, orgID), cfg: s.cfg}
+}
+
+// Reserve 为当前用户预留库存，可售库存不足时返回 ErrInsufficientStock。
+// 每个用户同时有效的预留数受 RESERVATION_MAX_ACTIVE 限制，防止单个账户占满产品的可售库存
+func (s *ReservationService) Reserve(productID uint, req *models.ReserveStockRequest, actor *Actor) (*models.StockReservation, error) {
+	ttl := reservationTTL(s.cfg, req.TTLSeconds)
+
+	var reservation *models.StockReservation
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 锁定用户行，串行化同一用户的预留，保证有效预留数的检查不被并发请求绕过
+		if err := lockForUpdate(tenant.System(tx)).Select("id").First(&models.User{}, actor.UserID).Error; err != nil {
+			return err
+		}
+		var active int64
+		if err := tx.Model(&models.StockReservation{}).Where("user_id = ?", actor.UserID).Where(activeReservationSQL).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active >= int64(s.cfg.ReservationMaxActive) {
+			return ErrTooManyReservations
+		}
+
+		var err error
+		reservation, err = reserveStock(tx, productID, req.VariantID, req.Quantity, actor.UserID, ttl)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// reservationTTL 预留时长：不提交时为 RESERVATION_TTL，不超过 RESERVATION_MAX_TTL。
+// 先按秒比较再换算，超大的秒数换算为 Duration 时会溢出为负数
+func reservationTTL(cfg *config.Config, seconds int) time.Duration {
+	if seconds <= 0 {
+		return cfg.ReservationTTL
+	}
+	if int64(seconds) >= int64(cfg.ReservationMaxTTL/time.Second) {
+		return cfg.ReservationMaxTTL
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// GetReservation 获取预留，只有预留用户或拥有 products:manage 权限的管理员可以查看
+func (s *ReservationService) GetReservation(id uint, actor *Actor) (*models.StockReservation, error) {
+	var reservation models.StockReservation
+	if err := s.db.First(&reservation, id).Error; err != nil {
+		return nil, err
+	}
+	if !canManageReservation(&reservation, actor) {
+		return nil, ErrForbidden
+	}
+	return &reservation, nil
+}
+
+// Confirm 确认预留并扣减库存，已过期或已结束的预留不能确认
+func (s *ReservationService) Confirm(id uint, actor *Actor) (*models.StockReservation, error) {
+	var reservation *models.StockReservation
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		reservation, err = confirmReservation(tx, id, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// Release 释放预留，占用的可售库存立即恢复
+func (s *ReservationService) Release(id uint, actor *Actor) (*models.StockReservation, error) {
+	var reservation models.StockReservation
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockReservation(tx, id, actor, &reservation); err != nil {
+			return err
+		}
+		now := time.Now()
+		reservation.Status, reservation.ReleasedAt = models.ReservationReleased, &now
+		return tx.Model(&reservation).Updates(map[string]any{"status": reservation.Status, "released_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ExpireStale 将全部组织中已过期的预留标记为 expired，返回处理的数量
+func (s *ReservationService) ExpireStale() (int64, error) {
+	result := tenant.System(s.db).Model(&models.StockReservation{}).
+		Where("status = ? AND expires_at <= NOW()", models.ReservationActive).
+		Updates(map[string]any{"status": models.ReservationExpired, "released_at": gorm.Expr("NOW()")})
+	return result.RowsAffected, result.Error
+}
+
+// RunSweeper 按 RESERVATION_SWEEP_INTERVAL 定期释放过期预留，直到 ctx 结束
+func (s *ReservationService) RunSweeper(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.ReservationSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.ExpireStale(); err != nil {
+				log.Printf("Failed to expire stock reservations: %v", err)
+			} else if n > 0 {
+				log.Printf("Expired %d stock reservations", n)
+			}
+		}
+	}
+}
+
+// reserveStock 在事务中锁定产品并检查可售库存后创建预留
+func reserveStock(tx *gorm.DB, productID uint, variantID *uint, quantity int, userID uint, ttl time.Duration) (*models.StockReservation, error) {
+	// 锁定产品，串行化同一产品的预留
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error; err != nil {
+		return nil, err
+	}
+	var variants int64
+	if err := tx.Model(&models.ProductVariant{}).Where("product_id = ?", product.ID).Count(&variants).Error; err != nil {
+		return nil, err
+	}
+
+	stock := product.Stock
+	reserved := tx.Model(&models.StockReservation{}).Where(activeReservationSQL)
+	switch {
+	case variants > 0 && variantID == nil:
+		return nil, ErrVariantRequired
+	case variantID != nil:
+		var variant models.ProductVariant
+		if err := tx.Where("product_id = ?", product.ID).First(&variant, *variantID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrUnknownVariant
+			}
+			return nil, err
+		}
+		stock = variant.Stock
+		reserved = reserved.Where("variant_id = ?", variant.ID)
+	default:
+		reserved = reserved.Where("product_id = ?", product.ID)
+	}
+
+	var held int
+	if err := reserved.Select("COALESCE(SUM(quantity), 0)").Scan(&held).Error; err != nil {
+		return nil, err
+	}
+	if stock-held < quantity {
+		return nil, ErrInsufficientStock
+	}
+	now, err := databaseNow(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := &models.StockReservation{
+		ProductID: product.ID,
+		VariantID: variantID,
+		UserID:    userID,
+		Quantity:  quantity,
+		Status:    models.ReservationActive,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := tx.Create(reservation).Error; err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// confirmReservation 在事务中确认预留：先结束预留再扣减库存，扣减时不会把自身计入占用
+func confirmReservation(tx *gorm.DB, id uint, actor *Actor) (*models.StockReservation, error) {
+	var reservation models.StockReservation
+	if err := lockReservation(tx, id, actor, &reservation); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reservation.Status, reservation.ConfirmedAt = models.ReservationConfirmed, &now
+	if err := tx.Model(&reservation).Updates(map[string]any{"status": reservation.Status, "confirmed_at": now}).Error; err != nil {
+		return nil, err
+	}
+	if _, err := applyStockChange(tx, stockChange{
+		ProductID: reservation.ProductID,
+		VariantID: reservation.VariantID,
+		Delta:     -reservation.Quantity,
+		Type:      models.StockMovementSell,
+		Reason:    fmt.Sprintf("reservation #%d confirmed", reservation.ID),
+		ActorID:   &actor.UserID,
+	}); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// lockReservation 锁定预留并检查权限和状态
+func lockReservation(tx *gorm.DB, id uint, actor *Actor, reservation *models.StockReservation) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(reservation, id).Error; err != nil {
+		return err
+	}
+	if !canManageReservation(reservation, actor) {
+		return ErrForbidden
+	}
+	if reservation.Status != models.ReservationActive {
+		return ErrReservationNotActive
+	}
+	now, err := databaseNow(tx)
+	if err != nil {
+		return err
+	}
+	if !now.Before(reservation.ExpiresAt) {
+		return ErrReservationExpired
+	}
+	return nil
+}
+
+// databaseNow 数据库的当前时间。预留是否过期统一按数据库时钟判断，与 activeReservationSQL 中的 NOW() 一致，
+// 避免应用服务器与数据库之间的时钟偏差导致已被视为过期的预留仍能确认
+func databaseNow(tx *gorm.DB) (time.Time, error) {
+	var now time.Time
+	err := tx.Raw("SELECT NOW()").Scan(&now).Error
+	return now, err
+}
+
+// canManageReservation 预留只能由预留用户或拥有 products:manage 权限的管理员查看和操作
+func canManageReservation(reservation *models.StockReservation, actor *Actor) bool {
+	return reservation.UserID == actor.UserID || actor.Can(models.PermProductsManage)
+}
+
+// fillAvailability 计算产品及其变体的可售库存：库存减去预留中的数量
+func fillAvailability(db *gorm.DB, products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	var rows []struct {
+		ProductID uint
+		VariantID *uint
+		Quantity  int
+	}
+	if err := db.Model(&models.StockReservation{}).
+		Select("product_id, variant_id, SUM(quantity) AS quantity").
+		Where("product_id IN ?", ids).Where(activeReservationSQL).
+		Group("product_id, variant_id").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	byProduct := make(map[uint]int)
+	byVariant := make(map[uint]int)
+	for _, r := range rows {
+		byProduct[r.ProductID] += r.Quantity
+		if r.VariantID != nil {
+			byVariant[*r.VariantID] += r.Quantity
+		}
+	}
+
+	for i := range products {
+		p := &products[i]
+		p.Available = max(p.Stock-byProduct[p.ID], 0)
+		for j := range p.Variants {
+			v := &p.Variants[j]
+			v.Available = max(v.Stock-byVariant[v.ID], 0)
+		}
+	}
+	return nil
+}
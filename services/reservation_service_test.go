@@ -0,0 +1,30 @@
+package services
+
+import (
+	"go-webapi-example/config"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestReservationTTL(t *testing.T) {
+	cfg := &config.Config{ReservationTTL: 15 * time.Minute, ReservationMaxTTL: 2 * time.Hour}
+	tests := []struct {
+		seconds int
+		want    time.Duration
+	}{
+		{0, 15 * time.Minute},
+		{-5, 15 * time.Minute},
+		{60, time.Minute},
+		{7200, 2 * time.Hour},
+		{7201, 2 * time.Hour},
+		// 直接换算会溢出为负数
+		{math.MaxInt64 / 1000, 2 * time.Hour},
+		{math.MaxInt, 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := reservationTTL(cfg, tt.seconds); got != tt.want {
+			t.Errorf("reservationTTL(%d) = %v, want %v", tt.seconds, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"go-webapi-example/models"
+	"go-webapi-example/tenant"
+	"go-webapi-example/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevocationService 访问令牌撤销存储。
+// 单个令牌按 jti 撤销；用户的全部令牌通过递增 User.TokenVersion 一次性撤销。
+type RevocationService struct {
+	db *gorm.DB
+}
+
+// NewRevocationService 令牌属于账户而不是组织，服务使用跳过租户隔离的会话
+func NewRevocationService(db *gorm.DB) *RevocationService {
+	return &RevocationService{db: tenant.System(db)}
+}
+
+// RevokeToken 撤销单个访问令牌
+func (s *RevocationService) RevokeToken(claims *utils.Claims) error {
+	if claims.ID == "" {
+		return errors.New("token has no jti")
+	}
+
+	expiresAt := time.Now().Add(utils.AccessTokenTTL())
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	revoked := &models.RevokedToken{
+		JTI:       claims.ID,
+		UserID:    claims.UserID,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(revoked).Error; err != nil {
+		return err
+	}
+
+	// 顺便清理已经自然过期的撤销记录
+	return s.db.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{}).Error
+}
+
+// RevokeAllForUser 撤销用户的全部访问令牌和刷新令牌
+func (s *RevocationService) RevokeAllForUser(userID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// 使用 Unscoped 以便已软删除的用户也能完成撤销
+		if err := tx.Unscoped().Model(&models.User{}).Where("id = ?", userID).
+			UpdateColumn("token_version", gorm.Expr("token_version + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", time.Now()).Error
+	})
+}
+
+// IsRevoked 检查访问令牌是否已被撤销：令牌被单独撤销、用户已删除或停用、或令牌版本已过期
+func (s *RevocationService) IsRevoked(claims *utils.Claims) (bool, error) {
+	var count int64
+	if err := s.db.Model(&models.RevokedToken{}).Where("jti = ?", claims.ID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	var user models.User
+	if err := s.db.Select("id", "is_active", "token_version").First(&user, claims.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return !user.IsActive || user.TokenVersion != claims.TokenVersion, nil
+}
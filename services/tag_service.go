@@ -0,0 +1,74 @@
+package services
+
+import (
+	"go-webapi-example/models"
+	"go-webapi-example/tenant"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagService 产品标签，标签按组织隔离
+type TagService struct {
+	db *gorm.DB
+}
+
+func NewTagService(db *gorm.DB) *TagService {
+	return &TagService{db: db}
+}
+
+// ForTenant 返回限定在指定组织内的服务副本
+func (s *TagService) ForTenant(orgID uint) *TagService {
+	return &TagService{db: tenant.Scope(s.db, orgID)}
+}
+
+// FindOrCreate 按名称查找标签，不存在的自动创建。名称去除空白并转为小写
+func (s *TagService) FindOrCreate(names []string) ([]models.Tag, error) {
+	normalized := NormalizeTags(names)
+	tags := []models.Tag{}
+	if len(normalized) == 0 {
+		return tags, nil
+	}
+
+	missing := make([]models.Tag, 0, len(normalized))
+	for _, name := range normalized {
+		missing = append(missing, models.Tag{Name: name})
+	}
+	// 并发创建同名标签时由唯一索引去重
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&missing).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Where("name IN ?", normalized).Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// FindByName 按名称查找标签
+func (s *TagService) FindByName(name string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := s.db.Where("name = ?", NormalizeTag(name)).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// NormalizeTag 标签名称去除空白并转为小写
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// NormalizeTags 规范化标签名称并去掉空值和重复值
+func NormalizeTags(names []string) []string {
+	var normalized []string
+	for _, name := range names {
+		name = NormalizeTag(name)
+		if name != "" && !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	"go-webapi-example/config"
+	"go-webapi-example/models"
+	"go-webapi-example/tenant"
+	"go-webapi-example/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, please login again")
+)
+
+type TokenService struct {
+	db                  *gorm.DB
+	refreshTTL          time.Duration
+	rbacService         *RBACService
+	organizationService *OrganizationService
+}
+
+// NewTokenService 刷新令牌属于账户而不是组织，令牌相关查询使用跳过租户隔离的会话
+func NewTokenService(db *gorm.DB, cfg *config.Config) *TokenService {
+	return &TokenService{
+		db:                  tenant.System(db),
+		refreshTTL:          cfg.RefreshTokenTTL,
+		rbacService:         NewRBACService(db),
+		organizationService: NewOrganizationService(db),
+	}
+}
+
+// IssueTokens 登录成功后签发访问令牌，并开启一个新的刷新令牌族；mfa 表示本次登录是否通过了二步验证
+func (s *TokenService) IssueTokens(user *models.User, mfa bool) (*models.TokenResponse, error) {
+	familyID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	_, rawRefreshToken, err := s.createRefreshToken(s.db, user.ID, familyID, mfa)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildTokenResponse(user, rawRefreshToken, mfa)
+}
+
+// Refresh 使用刷新令牌换取新的令牌对，旧的刷新令牌立即失效（轮换）。
+// 如果已经被轮换过的令牌再次被使用，说明令牌可能已经泄露，整个令牌族都会被撤销。
+func (s *TokenService) Refresh(refreshToken string) (*models.TokenResponse, error) {
+	var stored models.RefreshToken
+	if err := s.db.Where("token_hash = ?", utils.HashToken(refreshToken)).First(&stored).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if stored.RevokedAt != nil {
+		// 被主动撤销（登出等）的令牌直接视为无效；被轮换过的令牌再次出现则视为泄露
+		if stored.ReplacedByID == nil {
+			return nil, ErrInvalidRefreshToken
+		}
+		if err := s.RevokeFamily(stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	var user models.User
+	if err := s.db.Where("is_active = ?", true).First(&user, stored.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	var rawRefreshToken string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 条件更新保证并发请求中只有一个能完成轮换
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", stored.ID).
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+
+		next, raw, err := s.createRefreshToken(tx, user.ID, stored.FamilyID, stored.MFA)
+		if err != nil {
+			return err
+		}
+		rawRefreshToken = raw
+
+		return tx.Model(&models.RefreshToken{}).Where("id = ?", stored.ID).Update("replaced_by_id", next.ID).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			if revokeErr := s.RevokeFamily(stored.FamilyID); revokeErr != nil {
+				return nil, revokeErr
+			}
+		}
+		return nil, err
+	}
+
+	return s.buildTokenResponse(&user, rawRefreshToken, stored.MFA)
+}
+
+// RevokeFamily 撤销令牌族中所有仍然有效的刷新令牌
+func (s *TokenService) RevokeFamily(familyID string) error {
+	return s.db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserRefreshToken 撤销属于指定用户的刷新令牌所在的整个令牌族（用于登出）
+func (s *TokenService) RevokeUserRefreshToken(userID uint, refreshToken string) error {
+	var stored models.RefreshToken
+	err := s.db.Where("token_hash = ? AND user_id = ?", utils.HashToken(refreshToken), userID).First(&stored).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+	return s.RevokeFamily(stored.FamilyID)
+}
+
+func (s *TokenService) createRefreshToken(tx *gorm.DB, userID uint, familyID string, mfa bool) (*models.RefreshToken, string, error) {
+	raw, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := &models.RefreshToken{
+		UserID:    userID,
+		TokenHash: utils.HashToken(raw),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+		MFA:       mfa,
+	}
+	if err := tx.Create(token).Error; err != nil {
+		return nil, "", err
+	}
+
+	return token, raw, nil
+}
+
+func (s *TokenService) buildTokenResponse(user *models.User, rawRefreshToken string, mfa bool) (*models.TokenResponse, error) {
+	permissions, err := s.rbacService.PermissionsForRole(user.Role)
+	if err != nil {
+		return nil, err
+	}
+	orgID, err := s.organizationService.DefaultOrganizationID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := utils.GenerateToken(utils.Claims{
+		UserID:         user.ID,
+		Email:          user.Email,
+		Role:           user.Role,
+		TokenVersion:   user.TokenVersion,
+		MFA:            mfa,
+		Permissions:    permissions,
+		OrganizationID: orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenResponse{
+		Token:        accessToken,
+		RefreshToken: rawRefreshToken,
+		ExpiresIn:    int64(utils.AccessTokenTTL().Seconds()),
+	}, nil
+}
@@ -2,24 +2,91 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"go-webapi-example/config"
+	"go-webapi-example/listing"
+	"go-webapi-example/mailer"
 	"go-webapi-example/models"
+	"go-webapi-example/tenant"
 	"go-webapi-example/utils"
+	"log"
+	"net/url"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var (
+	ErrIncorrectPassword         = errors.New("current password is incorrect")
+	ErrInvalidResetToken         = errors.New("invalid or expired password reset token")
+	ErrEmailNotVerified          = errors.New("email address has not been verified")
+	ErrInvalidVerificationToken  = errors.New("invalid or expired email verification token")
+	ErrEmailAlreadyVerified      = errors.New("email address is already verified")
+	ErrInvalidTwoFactorChallenge = errors.New("invalid or expired two-factor challenge")
+	ErrLastSuperAdmin            = errors.New("cannot remove the last active superadmin")
+	ErrCannotModifySelf          = errors.New("you cannot change your own role or status")
+	ErrSuperAdminRequired        = errors.New("only a superadmin can manage superadmin accounts")
+	ErrInvalidTransferTarget     = errors.New("superadmin can only be transferred to another active user")
+	ErrSharedAccount             = errors.New("user also belongs to other organizations; only a superadmin can change the account")
+)
+
+// UserListSpec 用户列表支持的过滤和排序字段
+var UserListSpec = listing.Spec{
+	Filters: []listing.Filter{
+		{Param: "role", Kind: listing.String, Where: "users.role = ?"},
+		{Param: "is_active", Kind: listing.Bool, Where: "users.is_active = ?"},
+		{Param: "email_verified", Kind: listing.Bool, Where: "users.email_verified = ?"},
+		{Param: "created_from", Kind: listing.Time, Where: "users.created_at >= ?"},
+		{Param: "created_to", Kind: listing.Time, Where: "users.created_at <= ?"},
+	},
+	Sorts: map[string]string{
+		"id":         "users.id",
+		"name":       "users.name",
+		"email":      "users.email",
+		"age":        "users.age",
+		"created_at": "users.created_at",
+	},
+	DefaultSort: "id",
+	TieBreaker:  "users.id",
+}
+
 type UserService struct {
-	db *gorm.DB
+	db                *gorm.DB
+	cfg               *config.Config
+	mailer            mailer.Mailer
+	tokenService      *TokenService
+	revocationService *RevocationService
+	twoFactorService  *TwoFactorService
+	loginGuard        *LoginGuardService
+	rbacService       *RBACService
 }
 
-func NewUserService(db *gorm.DB) *UserService {
-	return &UserService{db: db}
+func NewUserService(db *gorm.DB, cfg *config.Config) *UserService {
+	return &UserService{
+		db:                db,
+		cfg:               cfg,
+		mailer:            mailer.New(cfg),
+		tokenService:      NewTokenService(db, cfg),
+		revocationService: NewRevocationService(db),
+		twoFactorService:  NewTwoFactorService(db, cfg),
+		loginGuard:        NewLoginGuardService(db, cfg),
+		rbacService:       NewRBACService(db),
+	}
 }
 
+// ForTenant 返回限定在指定组织内的服务副本：用户查询只返回该组织成员，新建用户自动加入该组织
+func (s *UserService) ForTenant(orgID uint) *UserService {
+	scoped := *s
+	scoped.db = tenant.Scope(s.db, orgID)
+	return &scoped
+}
+
+// CreateUser 创建用户并加入当前组织，必须在 ForTenant 返回的服务上调用
 func (s *UserService) CreateUser(req *models.CreateUserRequest) (*models.User, error) {
-	// 检查邮箱是否已存在
+	// 检查邮箱是否已存在（邮箱全局唯一，跨组织检查）
 	var existingUser models.User
-	if err := s.db.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
+	if err := s.accounts().Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
 		return nil, errors.New("email already exists")
 	}
 
@@ -32,7 +99,10 @@ func (s *UserService) CreateUser(req *models.CreateUserRequest) (*models.User, e
 	// 设置默认角色
 	role := req.Role
 	if role == "" {
-		role = "user"
+		role = models.RoleUser
+	}
+	if err := s.checkRole(role); err != nil {
+		return nil, err
 	}
 
 	user := &models.User{
@@ -44,10 +114,21 @@ func (s *UserService) CreateUser(req *models.CreateUserRequest) (*models.User, e
 		IsActive: true,
 	}
 
-	if err := s.db.Create(user).Error; err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.Membership{UserID: user.ID, Role: models.OrgRoleMember}).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	// 新账户默认未验证邮箱，发送验证链接
+	if err := s.SendVerificationEmail(user); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+	}
+
 	return user, nil
 }
 
@@ -59,19 +140,34 @@ func (s *UserService) GetUserByID(id uint) (*models.User, error) {
 	return &user, nil
 }
 
-func (s *UserService) GetAllUsers() ([]models.User, error) {
-	var users []models.User
-	if err := s.db.Find(&users).Error; err != nil {
+// GetAccount 获取当前用户自己的账户，不受组织限制；只用于用户本人的资料和认证流程
+func (s *UserService) GetAccount(id uint) (*models.User, error) {
+	var user models.User
+	if err := s.accounts().First(&user, id).Error; err != nil {
 		return nil, err
 	}
-	return users, nil
+	return &user, nil
+}
+
+// ListUsers 按列表参数查询一页用户，同时返回符合过滤条件的总数
+func (s *UserService) ListUsers(params *listing.Params) (*listing.Page[models.User], error) {
+	return listing.Query[models.User](s.db, params)
 }
 
-func (s *UserService) UpdateUser(id uint, req *models.UpdateUserRequest) (*models.User, error) {
+// UpdateUser 修改用户资料。资料属于全局账户，管理员修改同时属于其他组织的用户时需要是超级管理员
+func (s *UserService) UpdateUser(id uint, req *models.UpdateUserRequest, actor *Actor) (*models.User, error) {
 	user, err := s.GetUserByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if !actor.CanModifyUser(user) {
+		return nil, ErrForbidden
+	}
+	if user.ID != actor.UserID {
+		if err := ensureAccountScope(s.db, user.ID, actor); err != nil {
+			return nil, err
+		}
+	}
 
 	if req.Name != "" {
 		user.Name = req.Name
@@ -87,48 +183,279 @@ func (s *UserService) UpdateUser(id uint, req *models.UpdateUserRequest) (*model
 	return user, nil
 }
 
-func (s *UserService) DeleteUser(id uint) error {
-	return s.db.Delete(&models.User{}, id).Error
+// DeleteUser 删除用户；超级管理员只能由超级管理员删除，且不能删除最后一个超级管理员。
+// 用户账户是全局的，同时属于其他组织的用户只能由超级管理员删除
+func (s *UserService) DeleteUser(id uint, actor *Actor) error {
+	user, err := s.GetUserByID(id)
+	if err != nil {
+		return err
+	}
+	if user.Role == models.RoleSuperAdmin && actor.Role != models.RoleSuperAdmin {
+		return ErrSuperAdminRequired
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureAccountScope(tx, user.ID, actor); err != nil {
+			return err
+		}
+		if user.Role == models.RoleSuperAdmin {
+			if err := ensureOtherSuperAdmin(tx, user.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(user).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// 已删除用户的令牌全部失效
+	return s.revocationService.RevokeAllForUser(id)
 }
 
-// Login 用户登录
-func (s *UserService) Login(req *models.LoginRequest) (*models.LoginResponse, error) {
+// UpdateUserRole 修改用户角色（提升或降级），并撤销该用户已签发的全部令牌。
+// 不能修改自己的角色；只有超级管理员可以授予或收回 superadmin 角色，且不能降级最后一个超级管理员。
+// 角色是全局的，同时属于其他组织的用户只能由超级管理员修改
+func (s *UserService) UpdateUserRole(id uint, role string, actor *Actor) (*models.User, error) {
+	if id == actor.UserID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+	if user.Role == models.RoleSuperAdmin && actor.Role != models.RoleSuperAdmin {
+		return nil, ErrSuperAdminRequired
+	}
+	if !actor.CanAssignRole(role) {
+		return nil, ErrSuperAdminRequired
+	}
+	if err := s.checkRole(role); err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureAccountScope(tx, user.ID, actor); err != nil {
+			return err
+		}
+		if user.Role == models.RoleSuperAdmin {
+			if err := ensureOtherSuperAdmin(tx, user.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(user).Update("role", role).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.revocationService.RevokeAllForUser(id); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserByID(id)
+}
+
+// SetUserActive 激活或停用用户，并撤销该用户已签发的全部令牌。
+// 不能停用自己，只有超级管理员可以停用超级管理员，且不能停用最后一个超级管理员。
+// 停用对全部组织生效，同时属于其他组织的用户只能由超级管理员激活或停用
+func (s *UserService) SetUserActive(id uint, active bool, actor *Actor) (*models.User, error) {
+	if id == actor.UserID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsActive == active {
+		return user, nil
+	}
+	if user.Role == models.RoleSuperAdmin && actor.Role != models.RoleSuperAdmin {
+		return nil, ErrSuperAdminRequired
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureAccountScope(tx, user.ID, actor); err != nil {
+			return err
+		}
+		if !active && user.Role == models.RoleSuperAdmin {
+			if err := ensureOtherSuperAdmin(tx, user.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(user).Update("is_active", active).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.revocationService.RevokeAllForUser(id); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserByID(id)
+}
+
+// TransferSuperAdmin 当前超级管理员将 superadmin 角色移交给另一个有效用户，自己降级为 admin；双方令牌全部失效
+func (s *UserService) TransferSuperAdmin(actor *Actor, targetID uint) (*models.User, error) {
+	if actor.Role != models.RoleSuperAdmin {
+		return nil, ErrSuperAdminRequired
+	}
+	if targetID == actor.UserID {
+		return nil, ErrInvalidTransferTarget
+	}
+
+	// 超级管理员是全局角色，移交不受当前组织限制
+	db := tenant.System(s.db)
+
+	var target models.User
+	if err := db.First(&target, targetID).Error; err != nil {
+		return nil, err
+	}
+	if !target.IsActive || target.Role == models.RoleSuperAdmin {
+		return nil, ErrInvalidTransferTarget
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND role = ? AND is_active = ?", actor.UserID, models.RoleSuperAdmin, true).
+			Update("role", models.RoleAdmin)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSuperAdminRequired
+		}
+		return tx.Model(&target).Update("role", models.RoleSuperAdmin).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range []uint{actor.UserID, target.ID} {
+		if err := s.revocationService.RevokeAllForUser(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return &target, nil
+}
+
+// Login 用户登录；失败次数按账户和客户端 IP 统计，超过阈值后返回 LoginThrottledError
+func (s *UserService) Login(req *models.LoginRequest, clientIP string) (*models.LoginResponse, error) {
+	if err := s.loginGuard.CheckIP(clientIP); err != nil {
+		return nil, err
+	}
+
 	var user models.User
-	if err := s.db.Where("email = ? AND is_active = ?", req.Email, true).First(&user).Error; err != nil {
+	if err := s.accounts().Where("email = ? AND is_active = ?", req.Email, true).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.CheckDummyPassword(req.Password)
+			if err := s.loginGuard.RecordFailure(nil, req.Email, clientIP); err != nil {
+				return nil, err
+			}
 			return nil, errors.New("invalid email or password")
 		}
 		return nil, err
 	}
 
+	if err := s.loginGuard.CheckAccount(&user); err != nil {
+		return nil, err
+	}
+
 	// 验证密码
 	if !utils.CheckPassword(req.Password, user.Password) {
+		if err := s.loginGuard.RecordFailure(&user, req.Email, clientIP); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("invalid email or password")
 	}
 
-	// 生成 JWT token
-	token, err := utils.GenerateToken(user.ID, user.Email, user.Role)
+	if s.cfg.RequireEmailVerification && !user.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	// 已启用二步验证的账户第一步只返回短期挑战令牌，失败计数在第二步成功后才清空
+	if user.TwoFactorEnabled {
+		challenge, err := utils.GenerateActionToken(utils.PurposeTwoFactorChallenge, user.ID, user.Email, s.cfg.TwoFactorChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &models.LoginResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+		}, nil
+	}
+
+	return s.completeLogin(&user, false, clientIP)
+}
+
+// LoginWithTwoFactor 二步验证登录第二步：使用挑战令牌和验证码（或恢复码）换取访问令牌
+func (s *UserService) LoginWithTwoFactor(req *models.TwoFactorLoginRequest, clientIP string) (*models.LoginResponse, error) {
+	if err := s.loginGuard.CheckIP(clientIP); err != nil {
+		return nil, err
+	}
+
+	claims, err := utils.ParseActionToken(utils.PurposeTwoFactorChallenge, req.ChallengeToken)
+	if err != nil {
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+
+	var user models.User
+	if err := s.accounts().Where("is_active = ?", true).First(&user, claims.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidTwoFactorChallenge
+		}
+		return nil, err
+	}
+	if user.Email != claims.Email || !user.TwoFactorEnabled {
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+
+	if err := s.loginGuard.CheckAccount(&user); err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorService.VerifyCode(&user, req.Code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			if err := s.loginGuard.RecordFailure(&user, user.Email, clientIP); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	return s.completeLogin(&user, true, clientIP)
+}
+
+// completeLogin 签发访问令牌和刷新令牌，构造登录响应
+func (s *UserService) completeLogin(user *models.User, mfa bool, clientIP string) (*models.LoginResponse, error) {
+	if err := s.loginGuard.RecordSuccess(user, clientIP); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.tokenService.IssueTokens(user, mfa)
 	if err != nil {
 		return nil, err
 	}
 
+	profile := user.Profile()
 	return &models.LoginResponse{
-		Token: token,
-		User: models.UserProfile{
-			ID:       user.ID,
-			Name:     user.Name,
-			Email:    user.Email,
-			Age:      user.Age,
-			Role:     user.Role,
-			IsActive: user.IsActive,
-		},
+		Token:                  tokens.Token,
+		RefreshToken:           tokens.RefreshToken,
+		ExpiresIn:              tokens.ExpiresIn,
+		User:                   &profile,
+		TwoFactorSetupRequired: !mfa && s.twoFactorService.RequiredForRole(user.Role),
 	}, nil
 }
 
 // GetUserByEmail 根据邮箱获取用户
 func (s *UserService) GetUserByEmail(email string) (*models.User, error) {
 	var user models.User
-	if err := s.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := s.accounts().Where("email = ?", email).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -138,7 +465,7 @@ func (s *UserService) GetUserByEmail(email string) (*models.User, error) {
 func (s *UserService) CreateSuperAdmin() error {
 	// 检查是否已存在超级管理员
 	var count int64
-	s.db.Model(&models.User{}).Where("role = ?", "superadmin").Count(&count)
+	s.accounts().Model(&models.User{}).Where("role = ?", models.RoleSuperAdmin).Count(&count)
 	if count > 0 {
 		return nil // 已存在超级管理员
 	}
@@ -149,14 +476,279 @@ func (s *UserService) CreateSuperAdmin() error {
 		return err
 	}
 
+	now := time.Now()
 	superAdmin := &models.User{
-		Name:     "Super Admin",
-		Email:    "admin@example.com",
-		Password: hashedPassword,
-		Age:      30,
-		Role:     "superadmin",
-		IsActive: true,
+		Name:            "Super Admin",
+		Email:           "admin@example.com",
+		Password:        hashedPassword,
+		Age:             30,
+		Role:            models.RoleSuperAdmin,
+		IsActive:        true,
+		EmailVerified:   true,
+		EmailVerifiedAt: &now,
+	}
+
+	var org models.Organization
+	if err := s.db.Where("slug = ?", models.DefaultOrganizationSlug).First(&org).Error; err != nil {
+		return err
+	}
+
+	return tenant.Scope(s.db, org.ID).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(superAdmin).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.Membership{UserID: superAdmin.ID, Role: models.OrgRoleOwner}).Error
+	})
+}
+
+// ChangePassword 修改当前用户密码，需要验证当前密码；修改后该用户的全部令牌失效
+func (s *UserService) ChangePassword(userID uint, req *models.ChangePasswordRequest) error {
+	user, err := s.GetAccount(userID)
+	if err != nil {
+		return err
+	}
+
+	if !utils.CheckPassword(req.CurrentPassword, user.Password) {
+		return ErrIncorrectPassword
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := s.accounts().Model(user).Update("password", hashedPassword).Error; err != nil {
+		return err
+	}
+
+	return s.revocationService.RevokeAllForUser(user.ID)
+}
+
+// RequestPasswordReset 生成密码重置令牌并通过邮件发送重置链接。
+// 为避免泄露邮箱是否已注册，邮箱不存在时同样返回成功。
+func (s *UserService) RequestPasswordReset(email string) error {
+	var user models.User
+	if err := s.accounts().Where("email = ? AND is_active = ?", email, true).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	rawToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	resetToken := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(rawToken),
+		ExpiresAt: time.Now().Add(s.cfg.PasswordResetTTL),
+	}
+	if err := s.db.Create(resetToken).Error; err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.cfg.AppBaseURL, url.QueryEscape(rawToken))
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "重置密码",
+		Body: fmt.Sprintf("%s，您好：\n\n请在 %d 分钟内打开以下链接重置密码：\n%s\n\n如果这不是您本人的操作，请忽略此邮件。\n",
+			user.Name, int(s.cfg.PasswordResetTTL.Minutes()), link),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		log.Printf("Failed to send password reset email to user %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// ResetPassword 使用重置令牌设置新密码，令牌只能使用一次；重置后该用户的全部令牌失效
+func (s *UserService) ResetPassword(req *models.ResetPasswordRequest) error {
+	var resetToken models.PasswordResetToken
+	err := s.db.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", utils.HashToken(req.Token), time.Now()).
+		First(&resetToken).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	err = s.accounts().Transaction(func(tx *gorm.DB) error {
+		// 条件更新保证令牌只能被使用一次
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", resetToken.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidResetToken
+		}
+
+		// 同一用户其他未使用的重置令牌一并作废
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", resetToken.UserID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		result = tx.Model(&models.User{}).Where("id = ? AND is_active = ?", resetToken.UserID, true).
+			Update("password", hashedPassword)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidResetToken
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return s.db.Create(superAdmin).Error
+	return s.revocationService.RevokeAllForUser(resetToken.UserID)
+}
+
+// SendVerificationEmail 向用户发送带签名的邮箱验证链接
+func (s *UserService) SendVerificationEmail(user *models.User) error {
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, err := utils.GenerateActionToken(utils.PurposeEmailVerification, user.ID, user.Email, s.cfg.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/api/v1/auth/verify-email?token=%s", s.cfg.AppBaseURL, url.QueryEscape(token))
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "验证您的邮箱",
+		Body: fmt.Sprintf("%s，您好：\n\n请在 %d 小时内打开以下链接完成邮箱验证：\n%s\n",
+			user.Name, int(s.cfg.EmailVerificationTTL.Hours()), link),
+	})
+}
+
+// ResendVerificationEmail 根据邮箱重新发送验证链接；邮箱不存在或已验证时静默返回，避免泄露账户信息
+func (s *UserService) ResendVerificationEmail(email string) error {
+	var user models.User
+	if err := s.accounts().Where("email = ? AND is_active = ?", email, true).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := s.SendVerificationEmail(&user); err != nil && !errors.Is(err, ErrEmailAlreadyVerified) {
+		log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// VerifyEmail 校验邮箱验证令牌并标记邮箱已验证；用户修改邮箱后旧链接自动失效
+func (s *UserService) VerifyEmail(token string) (*models.User, error) {
+	claims, err := utils.ParseActionToken(utils.PurposeEmailVerification, token)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := s.GetAccount(claims.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+	if user.Email != claims.Email {
+		return nil, ErrInvalidVerificationToken
+	}
+	if user.EmailVerified {
+		return user, nil
+	}
+
+	return s.markEmailVerified(user)
+}
+
+// ForceVerifyEmail 管理员直接将用户邮箱标记为已验证
+func (s *UserService) ForceVerifyEmail(id uint) (*models.User, error) {
+	user, err := s.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.EmailVerified {
+		return user, nil
+	}
+
+	return s.markEmailVerified(user)
+}
+
+// markEmailVerified 调用方已经按组织或验证令牌确认了用户，按ID更新账户
+func (s *UserService) markEmailVerified(user *models.User) (*models.User, error) {
+	now := time.Now()
+	if err := s.accounts().Model(user).Updates(map[string]any{
+		"email_verified":    true,
+		"email_verified_at": now,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	user.EmailVerified = true
+	user.EmailVerifiedAt = &now
+	return user, nil
+}
+
+// accounts 登录、找回密码、验证邮箱等账户级操作按邮箱或用户ID定位账户，使用跳过租户隔离的会话
+func (s *UserService) accounts() *gorm.DB {
+	return tenant.System(s.db)
+}
+
+// checkRole 校验角色是否存在
+func (s *UserService) checkRole(role string) error {
+	exists, err := s.rbacService.RoleExists(role)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUnknownRole
+	}
+	return nil
+}
+
+// ensureAccountScope 用户行（资料、角色、状态）是全局的，组织管理员只能修改只属于当前组织的用户；
+// 用户同时是其他组织的成员时要求超级管理员操作。调用方已通过当前组织的作用域确认用户是本组织成员
+func ensureAccountScope(tx *gorm.DB, userID uint, actor *Actor) error {
+	if actor.Role == models.RoleSuperAdmin {
+		return nil
+	}
+	var memberships int64
+	if err := tenant.System(tx).Model(&models.Membership{}).Where("user_id = ?", userID).Count(&memberships).Error; err != nil {
+		return err
+	}
+	if memberships > 1 {
+		return ErrSharedAccount
+	}
+	return nil
+}
+
+// ensureOtherSuperAdmin 锁定全部有效的超级管理员（跨组织），确认除 userID 外至少还有一个，防止并发操作移除最后一个超级管理员
+func ensureOtherSuperAdmin(tx *gorm.DB, userID uint) error {
+	var ids []uint
+	if err := tenant.System(tx).Model(&models.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ? AND is_active = ?", models.RoleSuperAdmin, true).
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if id != userID {
+			return nil
+		}
+	}
+	return ErrLastSuperAdmin
 }
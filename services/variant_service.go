@@ -0,0 +1,265 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-webapi-example/models"
+	"go-webapi-example/money"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSKUExists        = errors.New("SKU is already used by another variant")
+	ErrDuplicateVariant = errors.New("variants of a product must have distinct SKUs and options")
+	ErrUnknownVariant   = errors.New("variant does not belong to this product")
+	ErrCurrencyMismatch = errors.New("prices must use the product currency")
+	ErrVariantPrice     = errors.New("each variant needs a price greater than zero in a supported currency")
+	ErrVariantReserved  = errors.New("variant has active stock reservations and cannot be removed")
+	ErrProductReserved  = errors.New("product has active stock reservations and cannot be converted to variants")
+)
+
+// replaceVariants 按请求整体替换产品的变体：带 ID 的更新已有变体，不带 ID 的新增，未列出的删除，
+// 然后重新汇总产品的价格区间和库存。已有变体的库存按差额调整，有有效预留的变体不能删除，
+// 有产品级有效预留的产品也不能改为按变体管理。
+// 变体库存的变化记入库存流水，价格的变化记入价格变动记录。调用方负责开启事务
+func replaceVariants(tx *gorm.DB, productID uint, reqs []models.ProductVariantRequest, actorID *uint) error {
+	if err := checkVariantRequests(reqs); err != nil {
+		return err
+	}
+
+	// 锁定产品和已有变体，与并发的库存变动串行执行
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error; err != nil {
+		return err
+	}
+	for _, req := range reqs {
+		if req.Price.Currency != product.Price.Currency {
+			return ErrCurrencyMismatch
+		}
+	}
+	var existing []models.ProductVariant
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("product_id = ?", productID).Find(&existing).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]*models.ProductVariant, len(existing))
+	for i := range existing {
+		byID[existing[i].ID] = &existing[i]
+	}
+
+	// SKU 在组织内唯一，本产品的变体之间可以交换或沿用 SKU
+	skus := make([]string, len(reqs))
+	for i, req := range reqs {
+		skus[i] = strings.TrimSpace(req.SKU)
+	}
+	if len(skus) > 0 {
+		var taken int64
+		if err := tx.Model(&models.ProductVariant{}).
+			Where("sku IN ? AND product_id <> ?", skus, productID).
+			Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			return ErrSKUExists
+		}
+	}
+
+	keep := make(map[uint]bool, len(reqs))
+	for _, req := range reqs {
+		if req.ID == 0 {
+			continue
+		}
+		if _, ok := byID[req.ID]; !ok {
+			return fmt.Errorf("%w: %d", ErrUnknownVariant, req.ID)
+		}
+		keep[req.ID] = true
+	}
+
+	// 流水中各条数量之和始终等于当前库存：产品改为按变体管理时转出产品自身的库存，删除变体时转出变体库存
+	movement := func(variantID *uint, typ string, delta, stockAfter int, reason string) error {
+		if delta == 0 {
+			return nil
+		}
+		_, err := recordMovement(tx, stockChange{
+			ProductID: productID, VariantID: variantID, Delta: delta, Type: typ, Reason: reason, ActorID: actorID,
+		}, stockAfter)
+		return err
+	}
+	if len(existing) == 0 && len(reqs) > 0 {
+		// 产品级预留不属于任何变体，改为按变体管理后无法再确认或占用库存
+		var reserved int64
+		if err := tx.Model(&models.StockReservation{}).
+			Where("product_id = ? AND variant_id IS NULL", productID).Where(activeReservationSQL).
+			Count(&reserved).Error; err != nil {
+			return err
+		}
+		if reserved > 0 {
+			return ErrProductReserved
+		}
+		if err := movement(nil, models.StockMovementAdjust, -product.Stock, 0, "stock moved to variants"); err != nil {
+			return err
+		}
+	}
+
+	var removed []uint
+	for _, v := range existing {
+		if !keep[v.ID] {
+			removed = append(removed, v.ID)
+		}
+	}
+	if len(removed) > 0 {
+		var reserved int64
+		if err := tx.Model(&models.StockReservation{}).
+			Where("variant_id IN ?", removed).Where(activeReservationSQL).
+			Count(&reserved).Error; err != nil {
+			return err
+		}
+		if reserved > 0 {
+			return ErrVariantReserved
+		}
+		for _, id := range removed {
+			if err := movement(&id, models.StockMovementAdjust, -byID[id].Stock, 0, "variant removed"); err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("id IN ?", removed).Delete(&models.ProductVariant{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(reqs) == 0 && len(existing) > 0 {
+		// 删除全部变体后产品没有库存，之后直接管理产品库存
+		if err := tx.Model(&models.Product{}).Where("id = ?", productID).Update("stock", 0).Error; err != nil {
+			return err
+		}
+	}
+
+	// 先把保留变体的 SKU 改为临时值，避免变体之间交换 SKU 时触发唯一索引
+	if len(keep) > 0 {
+		if err := tx.Model(&models.ProductVariant{}).
+			Where("id IN ?", slices.Collect(maps.Keys(keep))).
+			Update("sku", gorm.Expr("'~' || id::text")).Error; err != nil {
+			return err
+		}
+	}
+
+	for i, req := range reqs {
+		options := req.Options
+		if options == nil {
+			options = map[string]string{}
+		}
+		variant := models.ProductVariant{
+			ProductID: productID,
+			SKU:       skus[i],
+			Options:   options,
+			Price:     *req.Price,
+			Position:  i,
+		}
+		if req.ID != 0 {
+			if err := tx.Model(&models.ProductVariant{ID: req.ID}).
+				Select("sku", "options", "price_amount", "price_currency", "position").
+				Updates(&variant).Error; err != nil {
+				return err
+			}
+			// 库存按差额调整，与其他库存变动一样不能低于预留中的数量；未提交 stock 时保持不变
+			if req.Stock != nil && *req.Stock != byID[req.ID].Stock {
+				if _, err := applyStockChange(tx, stockChange{
+					ProductID: productID,
+					VariantID: &req.ID,
+					Delta:     *req.Stock - byID[req.ID].Stock,
+					Type:      models.StockMovementAdjust,
+					Reason:    "variant stock updated",
+					ActorID:   actorID,
+				}); err != nil {
+					return err
+				}
+			}
+			if err := recordPriceChange(tx, models.PriceChange{
+				ProductID: productID, VariantID: &req.ID, OldPrice: byID[req.ID].Price, NewPrice: *req.Price,
+				Source: models.PriceChangeManual, ActorID: actorID,
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Stock != nil {
+			variant.Stock = *req.Stock
+		}
+		if err := tx.Create(&variant).Error; err != nil {
+			return err
+		}
+		if err := recordPriceChange(tx, models.PriceChange{
+			ProductID: productID, VariantID: &variant.ID, NewPrice: *req.Price, Source: models.PriceChangeCreated, ActorID: actorID,
+		}); err != nil {
+			return err
+		}
+		if err := movement(&variant.ID, models.StockMovementReceive, variant.Stock, variant.Stock, "initial stock"); err != nil {
+			return err
+		}
+	}
+
+	return syncVariantTotals(tx, productID)
+}
+
+// syncVariantTotals 有变体的产品以变体的最低价、最高价和库存之和作为产品的价格区间和库存，
+// 列表的价格、库存过滤和排序因此同样适用于有变体的产品。没有变体时保持产品自身的价格和库存
+func syncVariantTotals(tx *gorm.DB, productID uint) error {
+	var totals struct {
+		Count    int64
+		MinPrice int64
+		MaxPrice int64
+		Stock    int
+	}
+	if err := tx.Model(&models.ProductVariant{}).
+		Select("COUNT(*) AS count, COALESCE(MIN(price_amount), 0) AS min_price, COALESCE(MAX(price_amount), 0) AS max_price, COALESCE(SUM(stock), 0) AS stock").
+		Where("product_id = ?", productID).
+		Scan(&totals).Error; err != nil {
+		return err
+	}
+	if totals.Count == 0 {
+		return nil
+	}
+	return tx.Model(&models.Product{}).Where("id = ?", productID).Updates(map[string]any{
+		"price_amount":     totals.MinPrice,
+		"max_price_amount": totals.MaxPrice,
+		"stock":            totals.Stock,
+	}).Error
+}
+
+// checkVariantRequests 每个变体都要有大于 0 的有效价格，同一产品的变体 SKU 不能重复，规格属性组合也不能重复
+func checkVariantRequests(reqs []models.ProductVariantRequest) error {
+	skus := make(map[string]bool, len(reqs))
+	options := make(map[string]bool, len(reqs))
+	ids := make(map[uint]bool, len(reqs))
+	for _, req := range reqs {
+		// 产品货币取自变体价格，缺少价格或货币时不能让产品以空货币保存
+		if req.Price == nil || !money.Valid(req.Price.Currency) || req.Price.Amount <= 0 {
+			return ErrVariantPrice
+		}
+		sku := strings.TrimSpace(req.SKU)
+		key := optionsKey(req.Options)
+		if sku == "" || skus[sku] || options[key] || (req.ID != 0 && ids[req.ID]) {
+			return ErrDuplicateVariant
+		}
+		skus[sku], options[key], ids[req.ID] = true, true, true
+	}
+	return nil
+}
+
+// optionsKey 规格属性按名称排序后的规范表示
+func optionsKey(options map[string]string) string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%q=%q;", name, options[name])
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"go-webapi-example/config"
+	"io"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage 文件存储接口，key 为以 / 分隔的相对路径，例如 products/1/2/abc.jpg
+type Storage interface {
+	// Put 写入对象，已存在时覆盖
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Open 读取对象，对象不存在时返回 ErrNotFound
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+	// URL 对象的公开访问地址
+	URL(key string) string
+}
+
+// New 根据配置创建文件存储
+func New(cfg *config.Config) Storage {
+	switch cfg.StorageDriver {
+	case "s3":
+		return &S3Storage{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+			PublicURL: cfg.StoragePublicURL,
+		}
+	default:
+		return &LocalStorage{Dir: cfg.StorageLocalDir, BaseURL: cfg.StoragePublicURL}
+	}
+}
+
+func joinURL(base, key string) string {
+	for len(base) > 0 && base[len(base)-1] == '/' {
+		base = base[:len(base)-1]
+	}
+	return base + "/" + key
+}
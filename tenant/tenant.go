@@ -0,0 +1,152 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrMissingTenant  = errors.New("tenant scope required: query on organization-scoped model without an organization")
+	ErrTenantMismatch = errors.New("record belongs to a different organization")
+)
+
+type contextKey int
+
+const (
+	orgKey contextKey = iota
+	bypassKey
+)
+
+// MembershipScoped 通过成员关系（而不是 organization_id 列）归属组织的模型，
+// 返回按组织过滤的 SQL 条件，其中 ? 为组织ID
+type MembershipScoped interface {
+	TenantCondition() string
+}
+
+// WithOrganization 返回携带组织ID的上下文，之后的查询都限定在该组织内
+func WithOrganization(ctx context.Context, orgID uint) context.Context {
+	return context.WithValue(ctx, orgKey, orgID)
+}
+
+// FromContext 读取上下文中的组织ID
+func FromContext(ctx context.Context) (uint, bool) {
+	orgID, ok := ctx.Value(orgKey).(uint)
+	return orgID, ok
+}
+
+// Scope 返回限定在指定组织内的数据库会话
+func Scope(db *gorm.DB, orgID uint) *gorm.DB {
+	return db.WithContext(WithOrganization(contextOf(db), orgID))
+}
+
+// System 返回跳过租户隔离的数据库会话，仅用于迁移、认证和跨组织的系统级操作
+func System(db *gorm.DB) *gorm.DB {
+	return db.WithContext(context.WithValue(contextOf(db), bypassKey, true))
+}
+
+// Register 注册租户隔离回调：带 OrganizationID 字段的模型和实现 MembershipScoped 的模型
+// 在没有组织上下文时直接报错（默认拒绝），有组织上下文时分别按组织ID和成员关系过滤
+func Register(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("tenant:create", assignTenant); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", scopeTenant); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenant:row", scopeTenant); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", scopeTenant); err != nil {
+		return err
+	}
+	return cb.Delete().Before("gorm:delete").Register("tenant:delete", scopeTenant)
+}
+
+// scopeTenant 为查询、更新和删除追加组织过滤条件
+func scopeTenant(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || stmt.SQL.Len() > 0 || bypassed(stmt.Context) {
+		return
+	}
+	orgID, ok := FromContext(stmt.Context)
+
+	if field := stmt.Schema.LookUpField("OrganizationID"); field != nil {
+		if !ok {
+			db.AddError(ErrMissingTenant)
+			return
+		}
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: stmt.Table, Name: field.DBName}, Value: orgID},
+		}})
+		return
+	}
+
+	if scoped, isScoped := reflect.New(stmt.Schema.ModelType).Interface().(MembershipScoped); isScoped {
+		if !ok {
+			db.AddError(ErrMissingTenant)
+			return
+		}
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: scoped.TenantCondition(), Vars: []any{orgID}},
+		}})
+	}
+}
+
+// assignTenant 创建记录时自动填充组织ID，拒绝写入其他组织
+func assignTenant(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || bypassed(stmt.Context) {
+		return
+	}
+	field := stmt.Schema.LookUpField("OrganizationID")
+	if field == nil {
+		return
+	}
+	orgID, ok := FromContext(stmt.Context)
+	if !ok {
+		db.AddError(ErrMissingTenant)
+		return
+	}
+
+	assign := func(rv reflect.Value) {
+		value, zero := field.ValueOf(stmt.Context, rv)
+		if zero {
+			if err := field.Set(stmt.Context, rv, orgID); err != nil {
+				db.AddError(err)
+			}
+			return
+		}
+		if id, _ := value.(uint); id != orgID {
+			db.AddError(ErrTenantMismatch)
+		}
+	}
+
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			rv := reflect.Indirect(stmt.ReflectValue.Index(i))
+			if rv.Kind() == reflect.Struct {
+				assign(rv)
+			}
+		}
+	case reflect.Struct:
+		assign(stmt.ReflectValue)
+	}
+}
+
+func bypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassKey).(bool)
+	return bypass
+}
+
+func contextOf(db *gorm.DB) context.Context {
+	if db.Statement != nil && db.Statement.Context != nil {
+		return db.Statement.Context
+	}
+	return context.Background()
+}
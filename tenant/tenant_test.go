@@ -0,0 +1,122 @@
+package tenant
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type scopedRecord struct {
+	ID             uint
+	OrganizationID uint
+}
+
+type memberRecord struct {
+	ID uint
+}
+
+func (memberRecord) TenantCondition() string {
+	return "member_records.id IN (SELECT user_id FROM memberships WHERE organization_id = ?)"
+}
+
+type globalRecord struct {
+	ID uint
+}
+
+// dryRunDB 只生成 SQL、不连接数据库的会话
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestScopeFailsClosedWithoutTenant(t *testing.T) {
+	db := dryRunDB(t)
+
+	tests := []struct {
+		name  string
+		model any
+	}{
+		{"organization column", &scopedRecord{}},
+		{"membership scoped", &memberRecord{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := db.First(tt.model, 1).Error; !errors.Is(err, ErrMissingTenant) {
+				t.Fatalf("query without tenant: got %v, want ErrMissingTenant", err)
+			}
+			if err := System(db).First(tt.model, 1).Error; err != nil {
+				t.Fatalf("system query: %v", err)
+			}
+		})
+	}
+
+	if err := db.First(&globalRecord{}, 1).Error; err != nil {
+		t.Fatalf("unscoped model: %v", err)
+	}
+}
+
+func TestScopeAddsCondition(t *testing.T) {
+	db := dryRunDB(t)
+
+	tests := []struct {
+		name  string
+		model any
+		want  string
+	}{
+		{"organization column", &scopedRecord{}, `"scoped_records"."organization_id" = $`},
+		{"membership scoped", &memberRecord{}, "member_records.id IN (SELECT user_id FROM memberships WHERE organization_id = $"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := Scope(db, 7).First(tt.model, 1).Statement
+			if sql := stmt.SQL.String(); !strings.Contains(sql, tt.want) {
+				t.Fatalf("SQL %q does not contain %q", sql, tt.want)
+			}
+			if !containsVar(stmt.Vars, uint(7)) {
+				t.Fatalf("vars %v do not include the organization ID", stmt.Vars)
+			}
+		})
+	}
+}
+
+func TestAssignTenant(t *testing.T) {
+	db := dryRunDB(t)
+
+	record := &scopedRecord{}
+	if err := Scope(db, 7).Create(record).Error; err != nil {
+		t.Fatal(err)
+	}
+	if record.OrganizationID != 7 {
+		t.Fatalf("OrganizationID = %d, want 7", record.OrganizationID)
+	}
+
+	if err := Scope(db, 7).Create(&scopedRecord{OrganizationID: 8}).Error; !errors.Is(err, ErrTenantMismatch) {
+		t.Fatalf("create in other organization: got %v, want ErrTenantMismatch", err)
+	}
+	if err := db.Create(&scopedRecord{}).Error; !errors.Is(err, ErrMissingTenant) {
+		t.Fatalf("create without tenant: got %v, want ErrMissingTenant", err)
+	}
+}
+
+func containsVar(vars []any, want any) bool {
+	for _, v := range vars {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
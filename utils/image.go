@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+)
+
+// Thumbnail 将图片等比缩小到最长边不超过 maxSize，使用区域平均采样，
+// 目标像素取对应源区域全部像素的平均值，缩小倍数较大时也不会产生明显锯齿。图片本身足够小时只复制不放大
+func Thumbnail(src image.Image, maxSize int) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > maxSize || h > maxSize {
+		if w >= h {
+			tw, th = maxSize, max(h*maxSize/w, 1)
+		} else {
+			tw, th = max(w*maxSize/h, 1), maxSize
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		sy0 := b.Min.Y + y*h/th
+		sy1 := max(b.Min.Y+(y+1)*h/th, sy0+1)
+		for x := 0; x < tw; x++ {
+			sx0 := b.Min.X + x*w/tw
+			sx1 := max(b.Min.X+(x+1)*w/tw, sx0+1)
+
+			// RGBA() 返回预乘 alpha 的 16 位分量，直接平均即可正确处理透明度
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(pr), g+uint64(pg), bl+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -2,51 +2,159 @@ package utils
 
 import (
 	"errors"
+	"go-webapi-example/config"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("your-secret-key") // 在生产环境中应该从环境变量读取
+// accessTokenTTL 访问令牌有效期，由 InitJWT 根据配置设置
+var accessTokenTTL = 15 * time.Minute
+
+// jwtIssuer 令牌签发者（iss）
+var jwtIssuer = "go-webapi-example"
 
 type Claims struct {
+	UserID         uint     `json:"user_id"`
+	Email          string   `json:"email"`
+	Role           string   `json:"role"`
+	TokenVersion   int      `json:"ver"`              // 用户令牌版本，用户令牌被整体撤销时递增
+	MFA            bool     `json:"mfa,omitempty"`    // 是否通过了二步验证
+	Permissions    []string `json:"perms"`            // 签发时角色拥有的权限
+	OrganizationID uint     `json:"org_id,omitempty"` // 默认组织（可通过 X-Org-ID 请求头切换）
+	jwt.RegisteredClaims
+}
+
+// ActionClaims 用于特定操作的短期签名令牌（如邮箱验证链接），通过 aud 区分用途，不能当作访问令牌使用
+type ActionClaims struct {
 	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	Email  string `json:"email,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成 JWT token
-func GenerateToken(userID uint, email, role string) (string, error) {
+// 操作令牌用途
+const (
+	PurposeEmailVerification  = "email_verification"
+	PurposeTwoFactorChallenge = "two_factor_challenge"
+)
+
+// InitJWT 根据配置初始化 JWT 参数和签名密钥
+func InitJWT(cfg *config.Config) error {
+	if cfg.AccessTokenTTL > 0 {
+		accessTokenTTL = cfg.AccessTokenTTL
+	}
+	if cfg.JWTIssuer != "" {
+		jwtIssuer = cfg.JWTIssuer
+	}
+	return loadKeyring(cfg)
+}
+
+// AccessTokenTTL 返回访问令牌有效期
+func AccessTokenTTL() time.Duration {
+	return accessTokenTTL
+}
+
+// GenerateToken 生成 JWT token，自动填充 jti、签发时间和过期时间
+func GenerateToken(claims Claims) (string, error) {
+	jti, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        jti,
+		Issuer:    jwtIssuer,
+		ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+
+	return signToken(claims)
+}
+
+// ParseToken 解析 JWT token
+func ParseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := parseToken(tokenString, claims); err != nil {
+		return nil, err
+	}
+
+	// 带 aud 的是操作令牌，不能作为访问令牌使用
+	if len(claims.Audience) > 0 {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+// GenerateActionToken 生成指定用途的操作令牌
+func GenerateActionToken(purpose string, userID uint, email string, ttl time.Duration) (string, error) {
+	jti, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
 	now := time.Now()
-	claims := Claims{
+	claims := ActionClaims{
 		UserID: userID,
 		Email:  email,
-		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)), // 24小时过期
+			ID:        jti,
+			Issuer:    jwtIssuer,
+			Audience:  jwt.ClaimStrings{purpose},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return signToken(claims)
 }
 
-// ParseToken 解析 JWT token
-func ParseToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+// ParseActionToken 解析并校验指定用途的操作令牌
+func ParseActionToken(purpose, tokenString string) (*ActionClaims, error) {
+	claims := &ActionClaims{}
+	if err := parseToken(tokenString, claims, jwt.WithAudience(purpose)); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
 
+// signToken 使用当前签名密钥签名，并在头部写入 kid
+func signToken(claims jwt.Claims) (string, error) {
+	key, err := currentSigningKey()
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.signKey)
+}
+
+// parseToken 根据头部 kid 选择验证密钥并校验签名、签发者和有效期
+func parseToken(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) error {
+	opts = append(opts, jwt.WithValidMethods(validMethods()), jwt.WithIssuer(jwtIssuer))
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := verificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.verifyKey, nil
+	}, opts...)
+
+	if err != nil {
+		return err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	if !token.Valid {
+		return errors.New("invalid token")
 	}
 
-	return nil, errors.New("invalid token")
+	return nil
 }
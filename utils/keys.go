@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-webapi-example/config"
+	"go-webapi-example/models"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// devSecret 开发环境未配置任何密钥时使用的默认 HS256 密钥
+const devSecret = "your-secret-key"
+
+// signingKey 已加载的签名密钥
+type signingKey struct {
+	id         string
+	method     jwt.SigningMethod
+	signKey    any // []byte / *rsa.PrivateKey / *ecdsa.PrivateKey
+	verifyKey  any // []byte / *rsa.PublicKey / *ecdsa.PublicKey
+	activeFrom time.Time
+	retireAt   time.Time
+}
+
+// keyring 按 activeFrom 升序排列的签名密钥
+var keyring []*signingKey
+
+// loadKeyring 根据配置构建密钥环
+func loadKeyring(cfg *config.Config) error {
+	var keys []*signingKey
+	seen := make(map[string]bool)
+
+	for _, kc := range cfg.JWTKeys {
+		key, err := loadSigningKey(kc)
+		if err != nil {
+			return fmt.Errorf("jwt key %q: %w", kc.ID, err)
+		}
+		if seen[key.id] {
+			return fmt.Errorf("duplicate jwt key id %q", key.id)
+		}
+		seen[key.id] = true
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		secret := cfg.JWTSecret
+		if secret == "" {
+			if cfg.Environment == "production" {
+				return errors.New("no JWT signing keys configured, set JWT_KEYS_FILE or JWT_SECRET")
+			}
+			secret = devSecret
+		}
+		keys = append(keys, &signingKey{
+			id:        "default",
+			method:    jwt.SigningMethodHS256,
+			signKey:   []byte(secret),
+			verifyKey: []byte(secret),
+		})
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].activeFrom.Before(keys[j].activeFrom)
+	})
+	keyring = keys
+	return nil
+}
+
+func loadSigningKey(kc config.JWTKeyConfig) (*signingKey, error) {
+	if kc.ID == "" {
+		return nil, errors.New("kid is required")
+	}
+
+	key := &signingKey{
+		id:         kc.ID,
+		activeFrom: kc.ActiveFrom,
+		retireAt:   kc.RetireAt,
+	}
+
+	switch kc.Algorithm {
+	case "HS256":
+		if kc.Secret == "" {
+			return nil, errors.New("secret is required for HS256")
+		}
+		key.method = jwt.SigningMethodHS256
+		key.signKey = []byte(kc.Secret)
+		key.verifyKey = []byte(kc.Secret)
+	case "RS256":
+		pemData, err := readPrivateKeyPEM(kc)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemData)
+		if err != nil {
+			return nil, err
+		}
+		key.method = jwt.SigningMethodRS256
+		key.signKey = privateKey
+		key.verifyKey = &privateKey.PublicKey
+	case "ES256":
+		pemData, err := readPrivateKeyPEM(kc)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := jwt.ParseECPrivateKeyFromPEM(pemData)
+		if err != nil {
+			return nil, err
+		}
+		if privateKey.Curve != elliptic.P256() {
+			return nil, errors.New("ES256 requires a P-256 key")
+		}
+		key.method = jwt.SigningMethodES256
+		key.signKey = privateKey
+		key.verifyKey = &privateKey.PublicKey
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", kc.Algorithm)
+	}
+
+	return key, nil
+}
+
+func readPrivateKeyPEM(kc config.JWTKeyConfig) ([]byte, error) {
+	if kc.PrivateKey != "" {
+		return []byte(kc.PrivateKey), nil
+	}
+	if kc.PrivateKeyFile != "" {
+		return os.ReadFile(kc.PrivateKeyFile)
+	}
+	return nil, errors.New("private_key or private_key_file is required")
+}
+
+// usable 密钥在指定时间是否仍可用于验证
+func (k *signingKey) usable(now time.Time) bool {
+	return k.retireAt.IsZero() || now.Before(k.retireAt)
+}
+
+// currentSigningKey 返回当前用于签名的密钥：已生效且未退役的密钥中 activeFrom 最晚的一个
+func currentSigningKey() (*signingKey, error) {
+	now := time.Now()
+	for i := len(keyring) - 1; i >= 0; i-- {
+		key := keyring[i]
+		if !key.activeFrom.After(now) && key.usable(now) {
+			return key, nil
+		}
+	}
+	return nil, errors.New("no active JWT signing key")
+}
+
+// verificationKey 根据 kid 查找用于验证的密钥
+func verificationKey(kid string) (*signingKey, error) {
+	now := time.Now()
+	for _, key := range keyring {
+		if key.id == kid {
+			if !key.usable(now) {
+				return nil, errors.New("signing key has been retired")
+			}
+			return key, nil
+		}
+	}
+	return nil, errors.New("unknown signing key")
+}
+
+// validMethods 返回密钥环中使用的全部签名算法
+func validMethods() []string {
+	var methods []string
+	seen := make(map[string]bool)
+	for _, key := range keyring {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// PublicJWKS 返回所有未退役的非对称密钥（包括尚未生效的预发布密钥）的公钥集合。
+// HS256 共享密钥不会被公开。
+func PublicJWKS() models.JWKSet {
+	now := time.Now()
+	set := models.JWKSet{Keys: []models.JWK{}}
+
+	for _, key := range keyring {
+		if !key.usable(now) {
+			continue
+		}
+
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, models.JWK{
+				Kty: "RSA",
+				Use: "sig",
+				Alg: key.method.Alg(),
+				Kid: key.id,
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case *ecdsa.PublicKey:
+			size := (pub.Curve.Params().BitSize + 7) / 8
+			set.Keys = append(set.Keys, models.JWK{
+				Kty: "EC",
+				Use: "sig",
+				Alg: key.method.Alg(),
+				Kid: key.id,
+				Crv: pub.Curve.Params().Name,
+				X:   base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size))),
+				Y:   base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size))),
+			})
+		}
+	}
+
+	return set
+}
@@ -10,8 +10,17 @@ func HashPassword(password string) (string, error) {
 	return string(bytes), err
 }
 
+// dummyPasswordHash 与 HashPassword 相同代价的固定哈希，不对应任何账户的密码
+const dummyPasswordHash = "$2a$10$k.dhfDR2CTqiBA/Cm8daZuANBiXzCsf4BycUOQcR4eB/VFxIPn7yO"
+
 // CheckPassword 验证密码
 func CheckPassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// CheckDummyPassword 账户不存在时用固定哈希做一次同样代价的比较，使响应时间与密码错误时一致，
+// 避免通过登录耗时判断邮箱是否已注册
+func CheckDummyPassword(password string) {
+	_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+}
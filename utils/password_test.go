@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestDummyPasswordHashCost 固定哈希的代价必须与 HashPassword 一致，否则未注册邮箱的登录耗时仍然不同
+func TestDummyPasswordHashCost(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != bcrypt.DefaultCost {
+		t.Fatalf("dummy hash cost = %d, want %d", cost, bcrypt.DefaultCost)
+	}
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Key RFC 6238 附录 B 中 SHA1 用例的密钥
+const rfc6238Key = "12345678901234567890"
+
+// rfc6238Vectors RFC 6238 附录 B 的 SHA1 用例。RFC 给出 8 位验证码，6 位验证码为其后 6 位
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "94287082"},
+	{1111111109, "07081804"},
+	{1111111111, "14050471"},
+	{1234567890, "89005924"},
+	{2000000000, "69279037"},
+	{20000000000, "65353130"},
+}
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	for _, v := range rfc6238Vectors {
+		want := v.code[len(v.code)-totpDigits:]
+		if got := totpCode([]byte(rfc6238Key), v.unix/totpPeriod); got != want {
+			t.Errorf("T=%d: totpCode = %s, want %s", v.unix, got, want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte(rfc6238Key))
+
+	for _, v := range rfc6238Vectors {
+		now := time.Unix(v.unix, 0)
+		code := v.code[len(v.code)-totpDigits:]
+		step, ok := ValidateTOTP(secret, code, now)
+		if !ok || step != v.unix/totpPeriod {
+			t.Errorf("T=%d: ValidateTOTP = %d, %v, want %d, true", v.unix, step, ok, v.unix/totpPeriod)
+		}
+		// 小写密钥和前后空白同样接受
+		if _, ok := ValidateTOTP(strings.ToLower(secret), " "+code+" ", now); !ok {
+			t.Errorf("T=%d: lower-case secret rejected", v.unix)
+		}
+	}
+
+	code := rfc6238Vectors[1].code[2:] // T=1111111109，时间步 37037036
+	base := time.Unix(1111111109, 0)
+	tests := []struct {
+		name   string
+		offset time.Duration
+		ok     bool
+	}{
+		{"previous window", 30 * time.Second, true},
+		{"next window", -30 * time.Second, true},
+		{"two windows late", 60 * time.Second, false},
+		{"two windows early", -60 * time.Second, false},
+	}
+	for _, tt := range tests {
+		if _, ok := ValidateTOTP(secret, code, base.Add(tt.offset)); ok != tt.ok {
+			t.Errorf("%s: ValidateTOTP ok = %v, want %v", tt.name, ok, tt.ok)
+		}
+	}
+
+	for _, bad := range []string{"", "12345", "1234567", "07081805", "081805"} {
+		if _, ok := ValidateTOTP(secret, bad, base); ok {
+			t.Errorf("ValidateTOTP(%q) accepted", bad)
+		}
+	}
+	if _, ok := ValidateTOTP("not base32!", code, base); ok {
+		t.Error("invalid secret accepted")
+	}
+}